@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"flag"
 	"log"
@@ -12,11 +14,17 @@ import (
 	"time"
 
 	"github.com/modelcontextprotocol/registry/internal/api"
+	"github.com/modelcontextprotocol/registry/internal/audit"
 	"github.com/modelcontextprotocol/registry/internal/auth"
 	"github.com/modelcontextprotocol/registry/internal/config"
 	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/health"
+	"github.com/modelcontextprotocol/registry/internal/idempotency"
+	"github.com/modelcontextprotocol/registry/internal/metrics"
 	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/ratelimit"
 	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/tracing"
 )
 
 func main() {
@@ -43,11 +51,24 @@ func main() {
 	// Initialize configuration
 	cfg := config.NewConfig()
 
+	// Initialize tracing before anything that creates spans
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		log.Printf("Failed to set up tracing: %v", err)
+		return
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("Error shutting down tracing: %v", err)
+		}
+	}()
+
 	// Initialize services based on environment
 	switch cfg.DatabaseType {
 	case config.DatabaseTypeMemory:
 		db = database.NewMemoryDB(map[string]*model.Server{})
-		registryService = service.NewRegistryServiceWithDB(db)
 	case config.DatabaseTypeMongoDB:
 		// Use MongoDB for real registry service in production/other environments
 		// Create a context with timeout for MongoDB connection
@@ -61,8 +82,6 @@ func main() {
 			return
 		}
 
-		// Create registry service with MongoDB
-		registryService = service.NewRegistryServiceWithDB(db)
 		log.Printf("MongoDB database name: %s", cfg.DatabaseName)
 		log.Printf("MongoDB collection name: %s", cfg.CollectionName)
 
@@ -79,24 +98,71 @@ func main() {
 		return
 	}
 
-	// Import seed data if requested (works for both memory and MongoDB)
+	// Readiness checks use the database directly, so probes don't show up in its metrics
+	var checks []health.Check
+	if connector, ok := db.(health.Connector); ok {
+		checks = append(checks, health.DatabaseCheck(connector))
+	}
+
+	// Instrument the database and create the registry service on top of it
+	db = tracing.InstrumentDatabase(db, string(cfg.DatabaseType))
+	if cfg.MetricsEnabled {
+		db = metrics.InstrumentDatabase(db, string(cfg.DatabaseType))
+	}
+	registryService = service.NewRegistryServiceWithDB(db)
+
+	// Import seed data in the background if requested (works for both memory and MongoDB).
+	// The registry reports itself as not ready until the import has finished.
+	seedImport := health.NewTask()
+	checks = append(checks, seedImport.Check("seed_import"))
 	if cfg.SeedImport {
-		log.Println("Importing data...")
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-		defer cancel()
+		go importSeed(db, cfg.SeedFilePath, seedImport)
+	} else {
+		seedImport.Finish(nil)
+	}
 
-		if err := db.ImportSeed(ctx, cfg.SeedFilePath); err != nil {
-			log.Printf("Failed to import seed file: %v", err)
-		} else {
-			log.Println("Data import completed successfully")
-		}
+	if cfg.HealthCheckGitHub {
+		client := &http.Client{Transport: tracing.Transport(nil)}
+		checks = append(checks, health.HTTPCheck("github", client, cfg.GithubAPIURL))
 	}
+	readiness := health.NewReadiness(cfg.HealthCheckTimeout, cfg.HealthCacheTTL, checks...)
 
 	// Initialize authentication services
 	authService := auth.NewAuthService(cfg)
+	if cfg.MetricsEnabled {
+		authService = metrics.InstrumentAuth(authService)
+	}
+
+	// Initialize publish rate limiting
+	var rateLimitStore ratelimit.Store
+	switch cfg.RateLimitStore {
+	case config.RateLimitStoreMemory:
+		rateLimitStore = ratelimit.NewMemoryStore()
+	case config.RateLimitStoreDatabase:
+		rateLimitStore = ratelimit.NewDatabaseStore(db)
+	default:
+		log.Printf("Invalid rate limit store: %s; supported stores: %s, %s",
+			cfg.RateLimitStore, config.RateLimitStoreMemory, config.RateLimitStoreDatabase)
+		return
+	}
+	limiter := ratelimit.NewLimiter(cfg, rateLimitStore)
+
+	// Remember publish results so that clients can retry publishes safely
+	idempotencyStore := idempotency.NewStore(db, cfg.IdempotencyKeyTTL)
+
+	// Pagination cursors are signed; without a configured secret they only stay valid until restart
+	if cfg.CursorSecret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Printf("Failed to generate cursor secret: %v", err)
+			return
+		}
+		cfg.CursorSecret = hex.EncodeToString(secret)
+		log.Printf("MCP_REGISTRY_CURSOR_SECRET is not set; pagination cursors won't work across restarts or instances")
+	}
 
 	// Initialize HTTP server
-	server := api.NewServer(cfg, registryService, authService)
+	server := api.NewServer(cfg, registryService, authService, limiter, idempotencyStore, readiness, seedImport)
 
 	// Start server in a goroutine so it doesn't block signal handling
 	go func() {
@@ -124,3 +190,24 @@ func main() {
 
 	log.Println("Server exiting")
 }
+
+// importSeed imports the seed file into db and reports the outcome to task
+func importSeed(db database.Database, seedFilePath string, task *health.Task) {
+	log.Println("Importing data...")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	// Each created or updated entry is audited as it is imported
+	ctx = audit.WithActor(ctx, audit.SystemActor)
+	stats, err := db.ImportSeed(ctx, seedFilePath, func(previous, imported *model.ServerDetail) *model.AuditRecord {
+		return audit.SeedImportRecord(ctx, previous, imported)
+	})
+	if err != nil {
+		log.Printf("Failed to import seed file: %v", err)
+		task.Finish(err)
+		return
+	}
+	log.Printf("Data import completed successfully: %d servers, %d created, %d updated, %d unchanged, %d skipped, %d failed",
+		stats.Total, stats.Created, stats.Updated, stats.Unchanged, stats.Skipped, stats.Failed)
+	task.Finish(nil)
+}
@@ -0,0 +1,9 @@
+// Package docs embeds the registry's API documentation into the binary
+package docs
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI description of the registry API in YAML
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
@@ -0,0 +1,181 @@
+package integrationtests_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/registry/internal/api/openapi"
+	"github.com/modelcontextprotocol/registry/internal/api/router"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/health"
+	"github.com/modelcontextprotocol/registry/internal/idempotency"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/ratelimit"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAPIMatchesOpenAPIDescription sends traffic to every documented endpoint through the
+// validation middleware in enforce mode, so any drift between the handlers and
+// docs/openapi.yaml turns into a 400 or 500 response and fails the test
+func TestAPIMatchesOpenAPIDescription(t *testing.T) {
+	cfg := &config.Config{
+		Version:                  "test",
+		BatchGetMaxItems:         100,
+		CacheControlServerList:   "public, max-age=30",
+		CacheControlServerDetail: "public, max-age=300",
+	}
+	registryService := service.NewFakeRegistryService()
+	limiter := ratelimit.NewLimiter(cfg, ratelimit.NewMemoryStore())
+	readiness := health.NewReadiness(time.Second, time.Minute)
+	idempotencyStore := idempotency.NewStore(database.NewMemoryDB(nil), time.Hour)
+	seedImport := health.NewTask()
+	seedImport.Finish(nil)
+	mux := router.New(cfg, registryService, &MockAuthService{}, limiter, idempotencyStore, readiness, seedImport)
+
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+	validate, err := openapi.Middleware(doc, config.OpenAPIValidationEnforce, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	server := httptest.NewServer(validate(mux))
+	defer server.Close()
+
+	do := func(t *testing.T, method, path, token string, body any) *http.Response {
+		t.Helper()
+		var reader io.Reader
+		if body != nil {
+			payload, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(payload)
+		}
+		req, err := http.NewRequest(method, server.URL+path, reader)
+		require.NoError(t, err)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	// Find a server ID to fetch
+	resp := do(t, http.MethodGet, "/v0/servers?limit=1", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var list struct {
+		Servers []model.Server `json:"servers"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	require.NotEmpty(t, list.Servers)
+	serverID := list.Servers[0].ID
+
+	publish := map[string]any{
+		"name":        "io.github.testuser/openapi-server",
+		"description": "A server published by the OpenAPI conformance test",
+		"repository": map[string]string{
+			"url":    "https://github.com/testuser/openapi-server",
+			"source": "github",
+			"id":     "testuser/openapi-server",
+		},
+		"version_detail": map[string]string{"version": "1.0.0"},
+		"packages": []map[string]any{{
+			"registry_name": "npm",
+			"name":          "openapi-server",
+			"version":       "1.0.0",
+			"environment_variables": []map[string]any{{
+				"name":      "API_KEY",
+				"is_secret": true,
+			}},
+		}},
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   any
+		status int
+	}{
+		{"health", http.MethodGet, "/v0/health", "", nil, http.StatusOK},
+		{"liveness", http.MethodGet, "/v0/health/live", "", nil, http.StatusOK},
+		{"readiness", http.MethodGet, "/v0/health/ready", "", nil, http.StatusOK},
+		{"ping", http.MethodGet, "/v0/ping", "", nil, http.StatusOK},
+		{"list servers", http.MethodGet, "/v0/servers", "", nil, http.StatusOK},
+		{"list servers with invalid cursor", http.MethodGet, "/v0/servers?cursor=nope", "", nil, http.StatusBadRequest},
+		{"list servers with fields", http.MethodGet, "/v0/servers?fields=name,version_detail.version", "", nil, http.StatusOK},
+		{"list servers with unknown field", http.MethodGet, "/v0/servers?fields=nmae", "", nil, http.StatusBadRequest},
+		{"list servers sorted by name", http.MethodGet, "/v0/servers?sort=name&limit=1", "", nil, http.StatusOK},
+		{"list servers with total", http.MethodGet, "/v0/servers?sort=-release_date&include_total=true", "", nil, http.StatusOK},
+		{"list servers with invalid sort", http.MethodGet, "/v0/servers?sort=version", "", nil, http.StatusBadRequest},
+		{"list servers with invalid sorted cursor", http.MethodGet, "/v0/servers?sort=name&cursor=bogus", "", nil, http.StatusBadRequest},
+		{"server detail", http.MethodGet, "/v0/servers/" + serverID, "", nil, http.StatusOK},
+		{"server detail with fields", http.MethodGet, "/v0/servers/" + serverID + "?fields=name,packages.registry_name", "", nil, http.StatusOK},
+		{"client config without client", http.MethodGet, "/v0/servers/" + serverID + "/client-config", "", nil, http.StatusBadRequest},
+		{"client config without package", http.MethodGet, "/v0/servers/" + serverID + "/client-config?client=vscode", "", nil, http.StatusUnprocessableEntity},
+		{"unknown server", http.MethodGet, "/v0/servers/" + uuid.New().String(), "", nil, http.StatusNotFound},
+		{"list servers wrong method", http.MethodDelete, "/v0/servers", "", nil, http.StatusMethodNotAllowed},
+		{"batch get", http.MethodPost, "/v0/servers:batchGet", "", map[string]any{
+			"ids":  []string{serverID, uuid.New().String()},
+			"refs": []string{"io.github.testuser/missing@1.0.0"},
+		}, http.StatusOK},
+		{"batch get without keys", http.MethodPost, "/v0/servers:batchGet", "", map[string]any{}, http.StatusBadRequest},
+		{"export", http.MethodGet, "/v0/export", "", nil, http.StatusOK},
+		{"auth start", http.MethodPost, "/v0/auth/start", "", map[string]any{"method": "github"}, http.StatusOK},
+		{"auth start dns", http.MethodPost, "/v0/auth/start", "", map[string]any{"method": "dns", "repo_ref": "com.example/server"}, http.StatusOK},
+		{"auth start http", http.MethodPost, "/v0/auth/start", "", map[string]any{"method": "http", "repo_ref": "com.example/server"}, http.StatusOK},
+		{"auth start unsupported method", http.MethodPost, "/v0/auth/start", "", map[string]any{"method": "gitlab"}, http.StatusBadRequest},
+		{"auth status", http.MethodGet, "/v0/auth/status?token=mock_status_token", "", nil, http.StatusOK},
+		{"auth status without token", http.MethodGet, "/v0/auth/status", "", nil, http.StatusBadRequest},
+		{"publish", http.MethodPost, "/v0/publish", "token", publish, http.StatusCreated},
+		{"publish without token", http.MethodPost, "/v0/publish", "", publish, http.StatusUnauthorized},
+		{"publish duplicate", http.MethodPost, "/v0/publish", "token", publish, http.StatusBadRequest},
+		{"audit log without admin", http.MethodGet, "/v0/admin/audit", "token", nil, http.StatusForbidden},
+		{"openapi yaml", http.MethodGet, "/v0/openapi.yaml", "", nil, http.StatusOK},
+		{"openapi json", http.MethodGet, "/v0/openapi.json", "", nil, http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := do(t, tc.method, tc.path, tc.token, tc.body)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode, "response body: %s", body)
+		})
+	}
+
+	t.Run("idempotent publish", func(t *testing.T) {
+		publishWithKey := func(version string) *http.Response {
+			publish["version_detail"] = map[string]string{"version": version}
+			payload, err := json.Marshal(publish)
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/v0/publish", bytes.NewReader(payload))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer token")
+			req.Header.Set("Idempotency-Key", "openapi-conformance")
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		assert.Equal(t, http.StatusCreated, publishWithKey("2.0.0").StatusCode)
+		replayed := publishWithKey("2.0.0")
+		assert.Equal(t, http.StatusCreated, replayed.StatusCode)
+		assert.Equal(t, "true", replayed.Header.Get("Idempotent-Replayed"))
+		assert.Equal(t, http.StatusUnprocessableEntity, publishWithKey("3.0.0").StatusCode)
+	})
+}
@@ -11,7 +11,10 @@ import (
 
 	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
 	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/ratelimit"
 	"github.com/modelcontextprotocol/registry/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -37,15 +40,22 @@ func (m *MockAuthService) CheckAuthStatus(_ context.Context, statusToken string)
 	return "", fmt.Errorf("invalid status token")
 }
 
-func (m *MockAuthService) ValidateAuth(_ context.Context, authentication model.Authentication) (bool, error) {
+func (m *MockAuthService) ValidateAuth(ctx context.Context, authentication model.Authentication) (*auth.Identity, error) {
+	return m.Authenticate(ctx, authentication)
+}
+
+func (m *MockAuthService) Authenticate(_ context.Context, authentication model.Authentication) (*auth.Identity, error) {
 	// Simple validation: for testing purposes, accept any non-empty token
 	switch authentication.Method {
 	case model.AuthMethodGitHub:
-		return authentication.Token != "", nil
+		if authentication.Token == "" {
+			return nil, nil
+		}
+		return &auth.Identity{Method: model.AuthMethodGitHub, Subject: "testuser"}, nil
 	case model.AuthMethodNone:
-		return true, nil
+		return &auth.Identity{Method: model.AuthMethodNone, Subject: "anonymous"}, nil
 	default:
-		return false, auth.ErrUnsupportedAuthMethod
+		return nil, auth.ErrUnsupportedAuthMethod
 	}
 }
 
@@ -56,7 +66,7 @@ func TestPublishIntegration(t *testing.T) {
 	authService := &MockAuthService{}
 
 	// Create the publish handler
-	handler := v0.PublishHandler(registryService, authService)
+	handler := v0.PublishHandler(registryService, authService, ratelimit.NewLimiter(&config.Config{}, ratelimit.NewMemoryStore()), nil)
 
 	t.Run("successful publish with GitHub auth", func(t *testing.T) {
 		publishReq := model.PublishRequest{
@@ -129,7 +139,7 @@ func TestPublishIntegration(t *testing.T) {
 		assert.NotEmpty(t, response["id"], "Server ID should be generated")
 
 		// Verify the server was actually published by retrieving it
-		publishedServer, err := registryService.GetByID(response["id"])
+		publishedServer, err := registryService.GetByID(context.Background(), response["id"])
 		require.NoError(t, err)
 		assert.Equal(t, publishReq.ServerDetail.Name, publishedServer.Name)
 		assert.Equal(t, publishReq.ServerDetail.Description, publishedServer.Description)
@@ -344,7 +354,7 @@ func TestPublishIntegration(t *testing.T) {
 		assert.Contains(t, duplicateRecorder.Body.String(), "Failed to publish server details")
 
 		// Verify that only the first server was actually stored
-		retrievedServer, err := registryService.GetByID(firstServerDetail.ID)
+		retrievedServer, err := registryService.GetByID(context.Background(), firstServerDetail.ID)
 		require.NoError(t, err)
 		assert.Equal(t, firstServerDetail.Name, retrievedServer.Name)
 		assert.Equal(t, firstServerDetail.Description, retrievedServer.Description)
@@ -421,11 +431,11 @@ func TestPublishIntegration(t *testing.T) {
 		require.NotEmpty(t, secondVersionDetail.ID, "Server ID for second version should be generated")
 
 		// Verify both versions exist
-		firstRetrieved, err := registryService.GetByID(firstVersionDetail.ID)
+		firstRetrieved, err := registryService.GetByID(context.Background(), firstVersionDetail.ID)
 		require.NoError(t, err)
 		assert.Equal(t, "1.0.0", firstRetrieved.VersionDetail.Version)
 
-		secondRetrieved, err := registryService.GetByID(secondVersionDetail.ID)
+		secondRetrieved, err := registryService.GetByID(context.Background(), secondVersionDetail.ID)
 		require.NoError(t, err)
 		assert.Equal(t, "2.0.0", secondRetrieved.VersionDetail.Version)
 	})
@@ -496,12 +506,12 @@ func TestPublishIntegration(t *testing.T) {
 		assert.Contains(t, olderRecorder.Body.String(), "version", "Error message should mention version")
 
 		// Verify that only the newer version exists
-		newerRetrieved, err := registryService.GetByID(newerVersionDetail.ID)
+		newerRetrieved, err := registryService.GetByID(context.Background(), newerVersionDetail.ID)
 		require.NoError(t, err)
 		assert.Equal(t, "2.0.0", newerRetrieved.VersionDetail.Version)
 
 		// Verify the older version was not stored
-		_, err = registryService.GetByID(olderVersionDetail.ID)
+		_, err = registryService.GetByID(context.Background(), olderVersionDetail.ID)
 		assert.Error(t, err, "Older version should not have been stored")
 	})
 }
@@ -510,7 +520,7 @@ func TestPublishIntegration(t *testing.T) {
 func TestPublishIntegrationWithComplexPackages(t *testing.T) {
 	registryService := service.NewFakeRegistryService()
 	authService := &MockAuthService{}
-	handler := v0.PublishHandler(registryService, authService)
+	handler := v0.PublishHandler(registryService, authService, ratelimit.NewLimiter(&config.Config{}, ratelimit.NewMemoryStore()), nil)
 
 	t.Run("publish with complex package configuration", func(t *testing.T) {
 		serverDetail := &model.ServerDetail{
@@ -606,11 +616,16 @@ func TestPublishIntegrationWithComplexPackages(t *testing.T) {
 				{
 					TransportType: "http",
 					URL:           "http://localhost:8080/mcp",
-					Headers: []model.Input{
+					Headers: []model.KeyValueInput{
 						{
-							Description: "API Version Header",
-							Format:      model.FormatString,
-							Value:       "v1",
+							Name: "X-API-Version",
+							InputWithVariables: model.InputWithVariables{
+								Input: model.Input{
+									Description: "API Version Header",
+									Format:      model.FormatString,
+									Value:       "v1",
+								},
+							},
 						},
 					},
 				},
@@ -638,7 +653,7 @@ func TestPublishIntegrationWithComplexPackages(t *testing.T) {
 		assert.NotEmpty(t, response["id"], "Server ID should be generated")
 
 		// Verify the complex server was published correctly
-		publishedServer, err := registryService.GetByID(serverDetail.ID)
+		publishedServer, err := registryService.GetByID(context.Background(), serverDetail.ID)
 		require.NoError(t, err)
 
 		// Verify package details
@@ -661,13 +676,13 @@ func TestPublishIntegrationWithComplexPackages(t *testing.T) {
 func TestPublishIntegrationEndToEnd(t *testing.T) {
 	registryService := service.NewFakeRegistryService()
 	authService := &MockAuthService{}
-	handler := v0.PublishHandler(registryService, authService)
+	handler := v0.PublishHandler(registryService, authService, ratelimit.NewLimiter(&config.Config{}, ratelimit.NewMemoryStore()), nil)
 
 	t.Run("end-to-end publish and retrieve flow", func(t *testing.T) {
 		// Step 1: Get initial count of servers
-		initialServers, _, err := registryService.List("", 100)
+		initial, err := registryService.ListWithOptions(context.Background(), database.ListOptions{Limit: 100})
 		require.NoError(t, err)
-		initialCount := len(initialServers)
+		initialCount := len(initial.Servers)
 
 		// Step 2: Publish a new server
 		serverDetail := &model.ServerDetail{
@@ -703,19 +718,19 @@ func TestPublishIntegrationEndToEnd(t *testing.T) {
 		require.Equal(t, http.StatusCreated, recorder.Code)
 
 		// Step 3: Verify the count increased
-		updatedServers, _, err := registryService.List("", 100)
+		updated, err := registryService.ListWithOptions(context.Background(), database.ListOptions{Limit: 100})
 		require.NoError(t, err)
-		assert.Equal(t, initialCount+1, len(updatedServers))
+		assert.Equal(t, initialCount+1, len(updated.Servers))
 
 		// Step 4: Verify the server can be retrieved by ID
-		retrievedServer, err := registryService.GetByID(serverDetail.ID)
+		retrievedServer, err := registryService.GetByID(context.Background(), serverDetail.ID)
 		require.NoError(t, err)
 		assert.Equal(t, serverDetail.Name, retrievedServer.Name)
 		assert.Equal(t, serverDetail.Description, retrievedServer.Description)
 
 		// Step 5: Verify the server appears in the list
 		found := false
-		for _, server := range updatedServers {
+		for _, server := range updated.Servers {
 			if server.ID == serverDetail.ID {
 				found = true
 				assert.Equal(t, serverDetail.Name, server.Name)
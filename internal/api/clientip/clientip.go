@@ -0,0 +1,73 @@
+// Package clientip determines the IP address of the client that sent a request, which may be
+// behind trusted proxies such as a CDN or load balancer, and carries it through request contexts
+package clientip
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ForwardedForHeader is the header in which proxies append the address they received a request from
+const ForwardedForHeader = "X-Forwarded-For"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the client IP
+func NewContext(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, contextKey{}, ip)
+}
+
+// FromContext returns the client IP stored in ctx, or an empty string if there is none
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(contextKey{}).(string)
+	return ip
+}
+
+// FromRequest returns the IP address of the client that sent r. Forwarding headers can be
+// forged by anyone, so X-Forwarded-For is only used for requests that come from an address in
+// trusted, and only up to the first hop that isn't trusted: walking the header from the right,
+// the first untrusted address is the client.
+func FromRequest(r *http.Request, trusted []netip.Prefix) string {
+	peer := RemoteIP(r)
+	addr, err := netip.ParseAddr(peer)
+	if err != nil || !isTrusted(addr, trusted) {
+		return peer
+	}
+
+	client := addr.Unmap()
+	hops := strings.Split(strings.Join(r.Header.Values(ForwardedForHeader), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// Whoever added a malformed entry isn't a proxy we trust
+			break
+		}
+		client = hop.Unmap()
+		if !isTrusted(client, trusted) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// RemoteIP returns the IP address of the peer that r was received from
+func RemoteIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
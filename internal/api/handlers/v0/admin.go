@@ -0,0 +1,159 @@
+// Package v0 contains API handlers for version 0 of the API
+package v0
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+// AuditLogResponse is a paginated list of audit records
+type AuditLogResponse struct {
+	Records  []model.AuditRecord `json:"records"`
+	Metadata Metadata            `json:"metadata,omitempty"`
+}
+
+// AuditLogHandler returns a handler that lets admins query the audit log
+func AuditLogHandler(cfg *config.Config, registry service.RegistryService, authService auth.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			problem.MethodNotAllowed(w, r)
+			return
+		}
+
+		if _, ok := requireAdmin(w, r, cfg, authService); !ok {
+			return
+		}
+
+		query := r.URL.Query()
+
+		// Build filter from query parameters
+		filter := database.AuditFilter{
+			Actor:     query.Get("actor"),
+			Operation: model.AuditOperation(query.Get("operation")),
+			Target:    query.Get("target"),
+			TargetID:  query.Get("target_id"),
+		}
+		for key, t := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+			value := query.Get(key)
+			if value == "" {
+				continue
+			}
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid "+key+" parameter: must be an RFC 3339 timestamp")
+				return
+			}
+			*t = parsed
+		}
+
+		cursor := query.Get("cursor")
+
+		// Default limit if not specified
+		limit := 30
+		if limitStr := query.Get("limit"); limitStr != "" {
+			parsedLimit, err := strconv.Atoi(limitStr)
+			if err != nil {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidLimit, "Invalid limit parameter")
+				return
+			}
+			if parsedLimit <= 0 {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidLimit, "Limit must be greater than 0")
+				return
+			}
+			limit = min(parsedLimit, 100)
+		}
+
+		records, nextCursor, err := registry.ListAuditRecords(r.Context(), filter, cursor, limit)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Error retrieving audit records")
+			return
+		}
+
+		response := AuditLogResponse{
+			Records: records,
+		}
+		if nextCursor != "" {
+			response.Metadata = Metadata{
+				NextCursor: nextCursor,
+				Count:      len(records),
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Failed to encode response")
+			return
+		}
+	}
+}
+
+// requireAdmin authenticates the caller and checks that it is a configured admin identity.
+// On failure it writes the error response and returns false.
+func requireAdmin(w http.ResponseWriter, r *http.Request, cfg *config.Config, authService auth.Service) (*auth.Identity, bool) {
+	token := bearerToken(r)
+	if token == "" {
+		problem.Write(w, r, http.StatusUnauthorized, problem.CodeAuthRequired, "Authorization header is required")
+		return nil, false
+	}
+
+	identity, authenticated := authenticateAdmin(r.Context(), cfg.AdminIdentities, authService, token)
+	if identity == nil {
+		// Without configured admins there is no method to check the token with, and no caller
+		// could be an admin anyway
+		if !authenticated && len(cfg.AdminIdentities) > 0 {
+			problem.Write(w, r, http.StatusUnauthorized, problem.CodeAuthFailed, "Invalid authentication credentials")
+			return nil, false
+		}
+		problem.Write(w, r, http.StatusForbidden, problem.CodeForbidden, "Admin privileges are required")
+		return nil, false
+	}
+
+	return identity, true
+}
+
+// authenticateAdmin tries the token with the auth method of each admin identity, since the token
+// alone doesn't say which method issued it, and returns the admin identity it belongs to.
+// authenticated reports whether the token was valid for any method, admin or not.
+func authenticateAdmin(
+	ctx context.Context, adminIdentities []string, authService auth.Service, token string,
+) (_ *auth.Identity, authenticated bool) {
+	tried := map[model.AuthMethod]bool{}
+	for _, admin := range adminIdentities {
+		method, subject, ok := strings.Cut(admin, ":")
+		if !ok {
+			continue
+		}
+		authentication := model.Authentication{Method: model.AuthMethod(method), Token: token}
+		switch authentication.Method {
+		case model.AuthMethodDNS, model.AuthMethodHTTP:
+			// Domain tokens don't identify their domain, so check the admin's
+			authentication.RepoRef = auth.DomainNamespace(subject)
+		default:
+			if tried[authentication.Method] {
+				continue
+			}
+			tried[authentication.Method] = true
+		}
+
+		identity, err := authService.Authenticate(ctx, authentication)
+		if err != nil || identity == nil {
+			continue
+		}
+		authenticated = true
+		if auth.IsAdmin(adminIdentities, identity) {
+			return identity, true
+		}
+	}
+	return nil, authenticated
+}
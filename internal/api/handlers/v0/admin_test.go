@@ -0,0 +1,148 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuditLogHandler(t *testing.T) {
+	adminIdentity := &auth.Identity{Method: model.AuthMethodGitHub, Subject: "admin"}
+	cfg := &config.Config{AdminIdentities: []string{"github:admin", "gitlab:ops", "dns:example.com"}}
+
+	records := []model.AuditRecord{
+		{
+			ID:         "0190b2a0-0000-7000-8000-000000000002",
+			Timestamp:  time.Date(2025, 5, 26, 0, 0, 0, 0, time.UTC),
+			Actor:      "github:example",
+			AuthMethod: model.AuthMethodGitHub,
+			Operation:  model.AuditOperationPublish,
+			Target:     "io.github.example/test-server",
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		queryParams    string
+		authHeader     string
+		setupMocks     func(*MockRegistryService, *MockAuthService)
+		expectedStatus int
+		expectedBody   *v0.AuditLogResponse
+	}{
+		{
+			name:        "admin lists audit records with filters",
+			queryParams: "?actor=github:example&operation=publish&since=2025-05-25T00:00:00Z&limit=500",
+			authHeader:  "Bearer admin_token",
+			setupMocks: func(registry *MockRegistryService, authSvc *MockAuthService) {
+				authSvc.Mock.On("Authenticate", mock.Anything, model.Authentication{
+					Method: model.AuthMethodGitHub,
+					Token:  "admin_token",
+				}).Return(adminIdentity, nil)
+				registry.Mock.On("ListAuditRecords", database.AuditFilter{
+					Actor:     "github:example",
+					Operation: model.AuditOperationPublish,
+					Since:     time.Date(2025, 5, 25, 0, 0, 0, 0, time.UTC),
+				}, "", 100).Return(records, "next-id", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: &v0.AuditLogResponse{
+				Records:  records,
+				Metadata: v0.Metadata{NextCursor: "next-id", Count: 1},
+			},
+		},
+		{
+			name:       "admin authenticated with another method",
+			authHeader: "Bearer admin_token",
+			setupMocks: func(registry *MockRegistryService, authSvc *MockAuthService) {
+				authSvc.Mock.On("Authenticate", mock.Anything, model.Authentication{
+					Method: model.AuthMethodGitHub,
+					Token:  "admin_token",
+				}).Return(nil, auth.ErrInvalidToken)
+				authSvc.Mock.On("Authenticate", mock.Anything, model.Authentication{
+					Method: model.AuthMethodGitLab,
+					Token:  "admin_token",
+				}).Return(nil, auth.ErrInvalidToken)
+				authSvc.Mock.On("Authenticate", mock.Anything, model.Authentication{
+					Method:  model.AuthMethodDNS,
+					Token:   "admin_token",
+					RepoRef: "com.example",
+				}).Return(&auth.Identity{Method: model.AuthMethodDNS, Subject: "example.com"}, nil)
+				registry.Mock.On("ListAuditRecords", database.AuditFilter{}, "", 30).Return(records, "", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   &v0.AuditLogResponse{Records: records},
+		},
+		{
+			name:       "invalid credentials",
+			authHeader: "Bearer bad_token",
+			setupMocks: func(_ *MockRegistryService, authSvc *MockAuthService) {
+				authSvc.Mock.On("Authenticate", mock.Anything, mock.Anything).Return(nil, auth.ErrInvalidToken)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing authorization header",
+			setupMocks:     func(_ *MockRegistryService, _ *MockAuthService) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "non-admin identity is forbidden",
+			authHeader: "Bearer user_token",
+			setupMocks: func(_ *MockRegistryService, authSvc *MockAuthService) {
+				authSvc.Mock.On("Authenticate", mock.Anything, mock.Anything).Return(testIdentity, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:        "invalid since parameter",
+			queryParams: "?since=yesterday",
+			authHeader:  "Bearer admin_token",
+			setupMocks: func(_ *MockRegistryService, authSvc *MockAuthService) {
+				authSvc.Mock.On("Authenticate", mock.Anything, mock.Anything).Return(adminIdentity, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRegistry := new(MockRegistryService)
+			mockAuthService := new(MockAuthService)
+			tc.setupMocks(mockRegistry, mockAuthService)
+
+			handler := v0.AuditLogHandler(cfg, mockRegistry, mockAuthService)
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/v0/admin/audit"+tc.queryParams, nil)
+			assert.NoError(t, err)
+			if tc.authHeader != "" {
+				req.Header.Set("Authorization", tc.authHeader)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+
+			if tc.expectedBody != nil {
+				var resp v0.AuditLogResponse
+				err = json.NewDecoder(rr.Body).Decode(&resp)
+				assert.NoError(t, err)
+				assert.Equal(t, *tc.expectedBody, resp)
+			}
+
+			mockRegistry.Mock.AssertExpectations(t)
+			mockAuthService.Mock.AssertExpectations(t)
+		})
+	}
+}
@@ -3,26 +3,36 @@ package v0
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/modelcontextprotocol/registry/internal/api/clientip"
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
+	"github.com/modelcontextprotocol/registry/internal/api/requestid"
+	"github.com/modelcontextprotocol/registry/internal/audit"
 	"github.com/modelcontextprotocol/registry/internal/auth"
 	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/ratelimit"
 )
 
-// StartAuthHandler handles requests to start an authentication flow
-func StartAuthHandler(authService auth.Service) http.HandlerFunc {
+// StartAuthHandler handles requests to start an authentication flow. Flows are started without
+// credentials, so they are rate limited by source IP.
+func StartAuthHandler(authService auth.Service, limiter *ratelimit.Limiter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Only allow POST method
 		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			problem.MethodNotAllowed(w, r)
 			return
 		}
 
 		// Read the request body
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			http.Error(w, "Error reading request body", http.StatusBadRequest)
+			writeReadBodyError(w, r, err)
 			return
 		}
 		defer r.Body.Close()
@@ -34,13 +44,13 @@ func StartAuthHandler(authService auth.Service) http.HandlerFunc {
 		}
 		err = json.Unmarshal(body, &authReq)
 		if err != nil {
-			http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidPayload, "Invalid request payload: "+err.Error())
 			return
 		}
 
 		// Validate required fields
 		if authReq.Method == "" {
-			http.Error(w, "Auth method is required", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidInput, "Auth method is required")
 			return
 		}
 
@@ -49,27 +59,48 @@ func StartAuthHandler(authService auth.Service) http.HandlerFunc {
 		switch authReq.Method {
 		case "github":
 			method = model.AuthMethodGitHub
+		case "dns":
+			method = model.AuthMethodDNS
+		case "http":
+			method = model.AuthMethodHTTP
 		default:
-			http.Error(w, "Unsupported authentication method", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeUnsupportedAuthMethod, "Unsupported authentication method")
+			return
+		}
+
+		if err := limiter.AllowAuthFlow(r.Context(), sourceIP(r)); err != nil {
+			var exceeded *ratelimit.ExceededError
+			if errors.As(err, &exceeded) {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(exceeded.RetryAfter.Seconds()))))
+				problem.Write(w, r, http.StatusTooManyRequests, problem.CodeRateLimited, "Too many auth flows started: "+err.Error())
+				return
+			}
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Failed to check rate limit")
 			return
 		}
 
 		// Start auth flow
 		flowInfo, statusToken, err := authService.StartAuthFlow(r.Context(), method, authReq.RepoRef)
 		if err != nil {
-			http.Error(w, "Failed to start auth flow: "+err.Error(), http.StatusInternalServerError)
+			problem.WriteError(w, r, err, "Failed to start auth flow")
 			return
 		}
 
+		// Status tokens are valid as long as the flow's codes
+		expiresIn, err := strconv.Atoi(flowInfo["expires_in"])
+		if err != nil {
+			expiresIn = 300 // 5 minutes
+		}
+
 		// Return successful response
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(map[string]interface{}{
 			"flow_info":    flowInfo,
 			"status_token": statusToken,
-			"expires_in":   300, // 5 minutes
+			"expires_in":   expiresIn,
 		}); err != nil {
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Failed to encode response")
 			return
 		}
 	}
@@ -80,35 +111,35 @@ func CheckAuthStatusHandler(authService auth.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Only allow GET method
 		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			problem.MethodNotAllowed(w, r)
 			return
 		}
 
 		// Get status token from query parameter
 		statusToken := r.URL.Query().Get("token")
 		if statusToken == "" {
-			http.Error(w, "Status token is required", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Status token is required")
 			return
 		}
 
 		// Check auth status
 		token, err := authService.CheckAuthStatus(r.Context(), statusToken)
 		if err != nil {
-			if err.Error() == "pending" {
+			if errors.Is(err, auth.ErrAuthPending) {
 				// Auth is still pending
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusOK)
 				if err := json.NewEncoder(w).Encode(map[string]interface{}{
 					"status": "pending",
 				}); err != nil {
-					http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+					problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Failed to encode response")
 					return
 				}
 				return
 			}
 
 			// Other error
-			http.Error(w, "Failed to check auth status: "+err.Error(), http.StatusInternalServerError)
+			problem.WriteError(w, r, err, "Failed to check auth status")
 			return
 		}
 
@@ -119,8 +150,50 @@ func CheckAuthStatusHandler(authService auth.Service) http.HandlerFunc {
 			"status": "complete",
 			"token":  token,
 		}); err != nil {
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Failed to encode response")
 			return
 		}
 	}
 }
+
+// bearerToken extracts the token from the Authorization header, accepting both
+// "Bearer <token>" and a bare token. It returns an empty string if the header is missing.
+func bearerToken(r *http.Request) string {
+	authHeader := r.Header.Get("Authorization")
+
+	// Handle bearer token format (e.g., "Bearer xyz123")
+	if len(authHeader) > 7 && strings.ToUpper(authHeader[:7]) == "BEARER " {
+		return authHeader[7:]
+	}
+	return authHeader
+}
+
+// actorFromRequest describes the authenticated caller of a request for the audit log
+func actorFromRequest(r *http.Request, identity *auth.Identity) audit.Actor {
+	return audit.Actor{
+		Identity:   identity.String(),
+		AuthMethod: identity.Method,
+		SourceIP:   sourceIP(r),
+		RequestID:  requestid.FromContext(r.Context()),
+	}
+}
+
+// sourceIP returns the IP address of the client the request came from, as determined by the
+// ClientIP middleware, or else the address of the peer that sent it
+func sourceIP(r *http.Request) string {
+	if ip := clientip.FromContext(r.Context()); ip != "" {
+		return ip
+	}
+	return clientip.RemoteIP(r)
+}
+
+// writeReadBodyError reports a failure to read the request body, distinguishing bodies
+// rejected by the size limit from other read errors
+func writeReadBodyError(w http.ResponseWriter, r *http.Request, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		problem.WriteError(w, r, err, "")
+		return
+	}
+	problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidPayload, "Error reading request body")
+}
@@ -0,0 +1,102 @@
+// Package v0 contains API handlers for version 0 of the API
+package v0
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+// BatchGetRequest lists the servers to fetch, by ID and/or as name@version references
+type BatchGetRequest struct {
+	IDs  []string `json:"ids,omitempty"`
+	Refs []string `json:"refs,omitempty"`
+}
+
+// BatchGetResponse holds the server details found and the requested IDs and references that
+// matched nothing
+type BatchGetResponse struct {
+	Servers []model.ServerDetail `json:"servers"`
+	Missing []string             `json:"missing"`
+}
+
+// BatchGetHandler returns a handler that fetches the details of many servers at once
+func BatchGetHandler(cfg *config.Config, registry service.RegistryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			problem.MethodNotAllowed(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeReadBodyError(w, r, err)
+			return
+		}
+		defer r.Body.Close()
+
+		var req BatchGetRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidPayload, "Invalid request payload: "+err.Error())
+			return
+		}
+
+		count := len(req.IDs) + len(req.Refs)
+		if count == 0 {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidInput, "At least one ID or reference is required")
+			return
+		}
+		if count > cfg.BatchGetMaxItems {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidInput,
+				"At most "+strconv.Itoa(cfg.BatchGetMaxItems)+" IDs and references may be requested at once")
+			return
+		}
+
+		for _, id := range req.IDs {
+			if _, err := uuid.Parse(id); err != nil {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidID, "Invalid server ID format: "+id)
+				return
+			}
+		}
+		refs := make([]database.ServerRef, 0, len(req.Refs))
+		for _, ref := range req.Refs {
+			// Versions never contain '@', whereas scoped package style names might
+			i := strings.LastIndex(ref, "@")
+			if i <= 0 || i == len(ref)-1 {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidInput,
+					"Invalid reference, expected name@version: "+ref)
+				return
+			}
+			refs = append(refs, database.ServerRef{Name: ref[:i], Version: ref[i+1:]})
+		}
+
+		servers, missing, err := registry.BatchGet(r.Context(), req.IDs, refs)
+		if err != nil {
+			problem.WriteError(w, r, err, "Error retrieving servers")
+			return
+		}
+
+		response := BatchGetResponse{Servers: servers, Missing: missing}
+		if response.Servers == nil {
+			response.Servers = []model.ServerDetail{}
+		}
+		if response.Missing == nil {
+			response.Missing = []string{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Error encoding batch get response: %v", err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package v0_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchGetHandler(t *testing.T) {
+	const (
+		idA = "8e3c3c7a-9f63-4d0a-9a4e-0a0c6b1f8a01"
+		idB = "8e3c3c7a-9f63-4d0a-9a4e-0a0c6b1f8a02"
+		idC = "8e3c3c7a-9f63-4d0a-9a4e-0a0c6b1f8a03"
+	)
+	registry := service.NewRegistryServiceWithDB(database.NewMemoryDB(map[string]*model.Server{
+		idA: {ID: idA, Name: "io.github.example/a", VersionDetail: model.VersionDetail{Version: "1.0.0"}},
+		idB: {ID: idB, Name: "io.github.example/b", VersionDetail: model.VersionDetail{Version: "2.0.0"}},
+	}))
+	handler := v0.BatchGetHandler(&config.Config{BatchGetMaxItems: 4}, registry)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v0/servers:batchGet", strings.NewReader(body)))
+		return rr
+	}
+
+	t.Run("found and missing", func(t *testing.T) {
+		rr := post(`{"ids":["` + idB + `","` + idC + `"],"refs":["io.github.example/a@1.0.0","io.github.example/b@2.0.0"]}`)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var response v0.BatchGetResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+		require.Len(t, response.Servers, 2)
+		assert.Equal(t, idB, response.Servers[0].ID)
+		assert.Equal(t, idA, response.Servers[1].ID)
+		assert.Equal(t, []string{idC}, response.Missing)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"empty":        `{}`,
+			"too many":     `{"ids":["` + idA + `","` + idB + `"],"refs":["a@1","b@2","c@3"]}`,
+			"invalid id":   `{"ids":["nope"]}`,
+			"invalid ref":  `{"refs":["io.github.example/a"]}`,
+			"invalid json": `{"ids":`,
+		} {
+			assert.Equal(t, http.StatusBadRequest, post(body).Code, name)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+// Package v0 contains API handlers for version 0 of the API
+package v0
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
+)
+
+// writeCacheableJSON encodes v as the JSON response body with a strong ETag computed from the
+// encoded bytes, plus the given Cache-Control value and Last-Modified time when they are set.
+// If the request's conditional headers show the client already has this representation,
+// it responds with 304 Not Modified and no body instead.
+func writeCacheableJSON(w http.ResponseWriter, r *http.Request, v any, cacheControl string, lastModified time.Time) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Failed to encode response")
+		return
+	}
+	// Match the trailing newline written by json.Encoder elsewhere in the API
+	body = append(body, '\n')
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	header := w.Header()
+	header.Set("ETag", etag)
+	if cacheControl != "" {
+		header.Set("Cache-Control", cacheControl)
+	}
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	header.Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// notModified evaluates If-None-Match and, in its absence, If-Modified-Since as described in RFC 9110
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" {
+				return true
+			}
+			// If-None-Match uses the weak comparison function
+			if strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		// HTTP dates have second precision
+		return !lastModified.Truncate(time.Second).After(t)
+	}
+
+	return false
+}
+
+// releaseTime parses a version release date, returning the zero time if it is missing or malformed
+func releaseTime(releaseDate string) time.Time {
+	t, err := time.Parse(time.RFC3339, releaseDate)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
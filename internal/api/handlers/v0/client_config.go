@@ -0,0 +1,74 @@
+// Package v0 contains API handlers for version 0 of the API
+package v0
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
+	"github.com/modelcontextprotocol/registry/internal/clientconfig"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+// ClientConfigHandler returns a handler that renders a server as the configuration block for
+// the MCP client named by the "client" query parameter
+func ClientConfigHandler(cfg *config.Config, registry service.RegistryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			problem.MethodNotAllowed(w, r)
+			return
+		}
+
+		id := r.PathValue("id")
+		if _, err := uuid.Parse(id); err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidID, "Invalid server ID format")
+			return
+		}
+
+		client := clientconfig.Client(r.URL.Query().Get("client"))
+		if client == "" {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter,
+				"The client parameter is required; supported clients: "+supportedClients())
+			return
+		}
+
+		serverDetail, err := registry.GetByID(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, database.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeNotFound, "Server not found")
+				return
+			}
+			problem.WriteError(w, r, err, "Error retrieving server details")
+			return
+		}
+
+		clientConfig, err := clientconfig.Generate(serverDetail, client)
+		switch {
+		case errors.Is(err, clientconfig.ErrUnsupportedClient):
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter,
+				"Unsupported client "+string(client)+"; supported clients: "+supportedClients())
+			return
+		case errors.Is(err, clientconfig.ErrNotInstallable):
+			problem.Write(w, r, http.StatusUnprocessableEntity, problem.CodeNotInstallable, err.Error())
+			return
+		case err != nil:
+			problem.WriteError(w, r, err, "Error generating client configuration")
+			return
+		}
+
+		writeCacheableJSON(w, r, clientConfig, cfg.CacheControlServerDetail, releaseTime(serverDetail.VersionDetail.ReleaseDate))
+	}
+}
+
+// supportedClients lists the supported client names for error messages
+func supportedClients() string {
+	names := make([]string, len(clientconfig.Clients))
+	for i, client := range clientconfig.Clients {
+		names[i] = string(client)
+	}
+	return strings.Join(names, ", ")
+}
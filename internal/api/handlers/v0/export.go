@@ -0,0 +1,108 @@
+// Package v0 contains API handlers for version 0 of the API
+package v0
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+const (
+	// ChangeSeqHeader carries the change sequence watermark of an export snapshot
+	ChangeSeqHeader = "X-Registry-Change-Seq"
+
+	// exportFlushInterval is the number of records written between flushes to the client
+	exportFlushInterval = 100
+)
+
+// ExportHandler streams every server detail as newline-delimited JSON from a consistent
+// point-in-time snapshot. The snapshot's change sequence watermark is sent in the
+// X-Registry-Change-Seq header. The response is gzip-compressed if the client accepts it.
+func ExportHandler(registry service.RegistryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			problem.MethodNotAllowed(w, r)
+			return
+		}
+
+		it, err := registry.Export(r.Context())
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Error opening export snapshot")
+			return
+		}
+		defer func() {
+			if err := it.Close(context.WithoutCancel(r.Context())); err != nil {
+				log.Printf("Error closing export snapshot: %v", err)
+			}
+		}()
+
+		header := w.Header()
+		header.Set("Content-Type", "application/x-ndjson")
+		header.Set(ChangeSeqHeader, strconv.FormatInt(it.ChangeSeq(), 10))
+		header.Set("Vary", "Accept-Encoding")
+
+		var out io.Writer = w
+		var gz *gzip.Writer
+		if acceptsGzip(r) {
+			header.Set("Content-Encoding", "gzip")
+			gz = gzip.NewWriter(w)
+			out = gz
+		}
+		w.WriteHeader(http.StatusOK)
+
+		rc := http.NewResponseController(w)
+		enc := json.NewEncoder(out)
+		count := 0
+		for it.Next(r.Context()) {
+			if err = enc.Encode(it.Server()); err != nil {
+				break
+			}
+			count++
+			if count%exportFlushInterval == 0 {
+				if gz != nil {
+					_ = gz.Flush()
+				}
+				_ = rc.Flush()
+			}
+		}
+		if err == nil {
+			err = it.Err()
+		}
+
+		if err != nil {
+			// The status has already been sent, so the only way to tell the client the export
+			// is incomplete is to abort the response without terminating it properly
+			log.Printf("Export aborted after %d records: %v", count, err)
+			panic(http.ErrAbortHandler)
+		}
+
+		if gz != nil {
+			if err := gz.Close(); err != nil {
+				log.Printf("Error finishing export stream: %v", err)
+			}
+		}
+	}
+}
+
+// acceptsGzip reports whether the Accept-Encoding header allows a gzip-encoded response
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "x-gzip" {
+			continue
+		}
+		// "gzip;q=0" explicitly refuses gzip
+		q := strings.ReplaceAll(strings.TrimSpace(params), " ", "")
+		return q != "q=0" && q != "q=0.0" && q != "q=0.00" && q != "q=0.000"
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package v0_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportHandler(t *testing.T) {
+	registry := service.NewRegistryServiceWithDB(database.NewMemoryDB(map[string]*model.Server{
+		"b": {ID: "b", Name: "io.github.example/b"},
+		"a": {ID: "a", Name: "io.github.example/a"},
+		"c": {ID: "c", Name: "io.github.example/c"},
+	}))
+	handler := v0.ExportHandler(registry)
+
+	readIDs := func(t *testing.T, body io.Reader) []string {
+		t.Helper()
+		var ids []string
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			var detail model.ServerDetail
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &detail))
+			ids = append(ids, detail.ID)
+		}
+		require.NoError(t, scanner.Err())
+		return ids
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v0/export", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "3", rr.Header().Get(v0.ChangeSeqHeader))
+		assert.Empty(t, rr.Header().Get("Content-Encoding"))
+		assert.Equal(t, []string{"a", "b", "c"}, readIDs(t, rr.Body))
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v0/export", nil)
+		req.Header.Set("Accept-Encoding", "br, gzip")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "gzip", rr.Header().Get("Content-Encoding"))
+		gz, err := gzip.NewReader(rr.Body)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}, readIDs(t, gz))
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v0/export", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	})
+}
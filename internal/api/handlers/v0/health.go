@@ -3,9 +3,12 @@ package v0
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
 
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/health"
 )
 
 type HealthResponse struct {
@@ -15,13 +18,67 @@ type HealthResponse struct {
 
 // HealthHandler returns a handler for health check endpoint
 func HealthHandler(cfg *config.Config) http.HandlerFunc {
-	return func(w http.ResponseWriter, _ *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(HealthResponse{
 			Status:         "ok",
 			GitHubClientID: cfg.GithubClientID,
 		}); err != nil {
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Failed to encode response")
+		}
+	}
+}
+
+// LivenessHandler returns a handler reporting that the process is up and serving requests.
+// It doesn't check dependencies, so a failing database never gets the process restarted.
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			problem.MethodNotAllowed(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if err := json.NewEncoder(w).Encode(map[string]string{"status": health.StatusOK}); err != nil {
+			log.Printf("Error encoding liveness response: %v", err)
+		}
+	}
+}
+
+// startupRetryAfter is the Retry-After sent while a startup task holds back requests
+const startupRetryAfter = "5"
+
+// AfterTask returns a handler that responds 503 until task has finished and hands requests to
+// next from then on. It keeps writes from racing startup work such as the seed import.
+func AfterTask(task *health.Task, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !task.Finished() {
+			w.Header().Set("Retry-After", startupRetryAfter)
+			problem.Write(w, r, http.StatusServiceUnavailable, problem.CodeUnavailable,
+				"The registry is still starting up; retry shortly")
+			return
+		}
+		next.ServeHTTP(w, r)
+	}
+}
+
+// ReadinessHandler returns a handler reporting whether the registry's dependencies are
+// available, with the status of each one. It responds 503 when the registry isn't ready.
+func ReadinessHandler(readiness *health.Readiness) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			problem.MethodNotAllowed(w, r)
+			return
+		}
+		report := readiness.Report(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if !report.Ready() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			log.Printf("Error encoding readiness response: %v", err)
 		}
 	}
 }
@@ -3,12 +3,14 @@ package v0_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/health"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -120,3 +122,22 @@ func TestHealthHandlerIntegration(t *testing.T) {
 	}
 	assert.Equal(t, expectedResp, healthResp)
 }
+
+func TestAfterTask(t *testing.T) {
+	task := health.NewTask()
+	handler := v0.AfterTask(task, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v0/publish", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, "5", rr.Header().Get("Retry-After"))
+	assert.Contains(t, rr.Body.String(), `"code":"unavailable"`)
+
+	// A failed task doesn't hold requests back forever
+	task.Finish(errors.New("seed file not found"))
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v0/publish", nil))
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
 	"github.com/modelcontextprotocol/registry/internal/config"
 )
 
@@ -12,7 +13,7 @@ import (
 func PingHandler(cfg *config.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			problem.MethodNotAllowed(w, r)
 			return
 		}
 
@@ -23,7 +24,7 @@ func PingHandler(cfg *config.Config) http.HandlerFunc {
 
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(response); err != nil {
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Failed to encode response")
 		}
 	}
 }
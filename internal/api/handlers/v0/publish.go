@@ -2,32 +2,64 @@
 package v0
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
+	"github.com/modelcontextprotocol/registry/internal/audit"
 	"github.com/modelcontextprotocol/registry/internal/auth"
-	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/idempotency"
+	"github.com/modelcontextprotocol/registry/internal/lint"
 	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/ratelimit"
 	"github.com/modelcontextprotocol/registry/internal/service"
 	"golang.org/x/net/html"
 )
 
-// PublishHandler handles requests to publish new server details to the registry
-func PublishHandler(registry service.RegistryService, authService auth.Service) http.HandlerFunc {
+// PublishResponse is the response to a successful publish
+type PublishResponse struct {
+	Message string `json:"message"`
+	ID      string `json:"id"`
+	// Warnings lists suspicious input definitions that didn't prevent publishing
+	Warnings []lint.Finding `json:"warnings,omitempty"`
+}
+
+// idempotencyRetryAfter is the Retry-After sent while a publish with the same key is in progress
+const idempotencyRetryAfter = "1"
+
+// PublishHandler handles requests to publish new server details to the registry. Requests with
+// an Idempotency-Key header are recorded in idempotencyStore, which may be nil.
+func PublishHandler(
+	registry service.RegistryService, authService auth.Service, limiter *ratelimit.Limiter,
+	idempotencyStore *idempotency.Store,
+) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Only allow POST method
 		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			problem.MethodNotAllowed(w, r)
 			return
 		}
 
+		idempotencyKey := r.Header.Get(idempotency.HeaderName)
+		if _, sent := r.Header[idempotency.HeaderName]; sent {
+			if err := idempotency.ValidateKey(idempotencyKey); err != nil {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, err.Error())
+				return
+			}
+		}
+
 		// Read the request body
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			http.Error(w, "Error reading request body", http.StatusBadRequest)
+			writeReadBodyError(w, r, err)
 			return
 		}
 		defer r.Body.Close()
@@ -36,7 +68,7 @@ func PublishHandler(registry service.RegistryService, authService auth.Service)
 		var publishReq model.PublishRequest
 		err = json.Unmarshal(body, &publishReq)
 		if err != nil {
-			http.Error(w, "Invalid request payload: "+err.Error(), http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidPayload, "Invalid request payload: "+err.Error())
 			return
 		}
 
@@ -45,40 +77,59 @@ func PublishHandler(registry service.RegistryService, authService auth.Service)
 
 		err = json.Unmarshal(body, &serverDetail)
 		if err != nil {
-			http.Error(w, "Invalid server detail payload: "+err.Error(), http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidPayload, "Invalid server detail payload: "+err.Error())
 			return
 		}
 		// Validate required fields
 		if serverDetail.Name == "" {
-			http.Error(w, "Name is required", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidInput, "Name is required")
 			return
 		}
 
 		// Version is required
 		if serverDetail.VersionDetail.Version == "" {
-			http.Error(w, "Version is required", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidInput, "Version is required")
 			return
 		}
 
-		// Get auth token from Authorization header
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+		// Digest the payload before publishing fills in the ID and release date
+		requestHash, err := idempotency.RequestHash(&serverDetail)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Failed to process request")
 			return
 		}
 
-		// Handle bearer token format (e.g., "Bearer xyz123")
-		token := authHeader
-		if len(authHeader) > 7 && strings.ToUpper(authHeader[:7]) == "BEARER " {
-			token = authHeader[7:]
+		// Reject input definitions that clients couldn't use; warnings are returned on success
+		findings := lint.ServerDetail(&serverDetail)
+		if lint.HasErrors(findings) {
+			p := problem.New(http.StatusBadRequest, problem.CodeInvalidInputDefinition,
+				"The server's input definitions are invalid; see findings")
+			p.Findings = findings
+			p.Write(w, r)
+			return
+		}
+
+		// Get auth token from Authorization header
+		token := bearerToken(r)
+		if token == "" {
+			problem.Write(w, r, http.StatusUnauthorized, problem.CodeAuthRequired, "Authorization header is required")
+			return
 		}
 
 		// Determine authentication method based on server name prefix
 		var authMethod model.AuthMethod
 		switch {
+		case strings.HasPrefix(serverDetail.Name, "io.github") && auth.IsJWT(token):
+			// GitHub Actions workflows publish with the OIDC token of their run
+			authMethod = model.AuthMethodGitHubOIDC
 		case strings.HasPrefix(serverDetail.Name, "io.github"):
 			authMethod = model.AuthMethodGitHub
-		// Additional cases can be added here for other prefixes
+		case strings.HasPrefix(serverDetail.Name, "io.gitlab."):
+			authMethod = model.AuthMethodGitLab
+		case auth.NamespaceDomain(serverDetail.Name) != "":
+			// Other reverse domain name namespaces are verified through DNS or a well-known file,
+			// depending on the flow that issued the token
+			authMethod = auth.DomainAuthMethod(token)
 		default:
 			// Keep the default auth method as AuthMethodNone
 			authMethod = model.AuthMethodNone
@@ -93,41 +144,125 @@ func PublishHandler(registry service.RegistryService, authService auth.Service)
 			RepoRef: serverName,
 		}
 
-		valid, err := authService.ValidateAuth(r.Context(), a)
+		identity, err := authService.ValidateAuth(r.Context(), a)
 		if err != nil {
-			if errors.Is(err, auth.ErrAuthRequired) {
-				http.Error(w, "Authentication is required for publishing", http.StatusUnauthorized)
-				return
+			switch {
+			case errors.Is(err, auth.ErrAuthRequired):
+				problem.Write(w, r, http.StatusUnauthorized, problem.CodeAuthRequired, "Authentication is required for publishing")
+			case errors.Is(err, auth.ErrGitHubRateLimited):
+				var limited *auth.RateLimitError
+				if errors.As(err, &limited) && !limited.Reset.IsZero() {
+					retryAfter := max(time.Until(limited.Reset), 0)
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				}
+				problem.WriteError(w, r, err, "Could not validate credentials")
+			default:
+				// Rejected credentials are 401s; failures to reach the services that check them
+				// aren't, and their messages stay out of the response
+				problem.WriteError(w, r, err, "Authentication failed")
 			}
-			http.Error(w, "Authentication failed: "+err.Error(), http.StatusUnauthorized)
 			return
 		}
 
-		if !valid {
-			http.Error(w, "Invalid authentication credentials", http.StatusUnauthorized)
+		if identity == nil {
+			problem.Write(w, r, http.StatusUnauthorized, problem.CodeAuthFailed, "Invalid authentication credentials")
 			return
 		}
 
-		// Call the publish method on the registry service
-		err = registry.Publish(&serverDetail)
+		// Reserve the key before publishing, so that concurrent retries wait for this request. A
+		// retry of a publish that already succeeded gets the original response, without counting
+		// against the rate limits.
+		completed := false
+		if idempotencyKey != "" {
+			replay, err := idempotencyStore.Reserve(r.Context(), identity.String(), idempotencyKey, requestHash)
+			switch {
+			case errors.Is(err, idempotency.ErrKeyReused):
+				problem.Write(w, r, http.StatusUnprocessableEntity, problem.CodeIdempotencyKeyReused,
+					"The Idempotency-Key was already used to publish a different payload")
+				return
+			case errors.Is(err, idempotency.ErrInProgress):
+				w.Header().Set("Retry-After", idempotencyRetryAfter)
+				problem.Write(w, r, http.StatusConflict, problem.CodeIdempotencyKeyInUse,
+					"A publish with this Idempotency-Key is still in progress; retry to get its response")
+				return
+			case err != nil:
+				log.Printf("Failed to reserve idempotency key: %v", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Failed to look up idempotency key")
+				return
+			case replay != nil:
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set(idempotency.ReplayedHeaderName, "true")
+				w.WriteHeader(replay.StatusCode)
+				_, _ = w.Write(replay.Body)
+				return
+			}
+
+			// A failed publish gives up the key, so that a retry is handled again. The release
+			// must happen even if the client has gone away.
+			defer func() {
+				if completed {
+					return
+				}
+				ctx := context.WithoutCancel(r.Context())
+				if err := idempotencyStore.Release(ctx, identity.String(), idempotencyKey); err != nil {
+					log.Printf("Failed to release idempotency key: %v", err)
+				}
+			}()
+		}
+
+		// Enforce per-identity and per-namespace publish limits
+		reservation, err := limiter.AllowPublish(r.Context(), identity, serverDetail.Name)
 		if err != nil {
-			// Check for specific error types and return appropriate HTTP status codes
-			if errors.Is(err, database.ErrInvalidVersion) || errors.Is(err, database.ErrAlreadyExists) {
-				http.Error(w, "Failed to publish server details: "+err.Error(), http.StatusBadRequest)
+			var exceeded *ratelimit.ExceededError
+			if errors.As(err, &exceeded) {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(exceeded.RetryAfter.Seconds()))))
+				problem.Write(w, r, http.StatusTooManyRequests, problem.CodeRateLimited, "Too many publish requests: "+err.Error())
 				return
 			}
-			http.Error(w, "Failed to publish server details: "+err.Error(), http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Failed to check rate limit")
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
-		if err := json.NewEncoder(w).Encode(map[string]string{
-			"message": "Server publication successful",
-			"id":      serverDetail.ID,
-		}); err != nil {
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		// Attach the authenticated actor so the publish is recorded in the audit log
+		ctx := audit.WithActor(r.Context(), actorFromRequest(r, identity))
+
+		// Call the publish method on the registry service
+		err = registry.Publish(ctx, &serverDetail)
+		if err != nil {
+			// Only published versions count against the namespace limit
+			if err := reservation.Release(context.WithoutCancel(r.Context())); err != nil {
+				log.Printf("Failed to release publish rate limit: %v", err)
+			}
+			// Check for specific error types and return appropriate HTTP status codes
+			problem.WriteError(w, r, err, "Failed to publish server details")
 			return
 		}
+
+		response, err := json.Marshal(PublishResponse{
+			Message:  "Server publication successful",
+			ID:       serverDetail.ID,
+			Warnings: findings,
+		})
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Failed to encode response")
+			return
+		}
+		// Match the trailing newline written by json.Encoder elsewhere in the API
+		response = append(response, '\n')
+
+		if idempotencyKey != "" {
+			// The server is published either way, so a failure to record the response only costs
+			// the client the ability to retry safely
+			completed = true
+			err := idempotencyStore.Complete(context.WithoutCancel(r.Context()), identity.String(), idempotencyKey,
+				requestHash, idempotency.Response{StatusCode: http.StatusCreated, Body: response})
+			if err != nil {
+				log.Printf("Failed to store idempotency key: %v", err)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(response)
 	}
 }
@@ -2,12 +2,19 @@
 package v0
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/pagination"
+	"github.com/modelcontextprotocol/registry/internal/projection"
 	"github.com/modelcontextprotocol/registry/internal/service"
 )
 
@@ -17,29 +24,58 @@ type PaginatedResponse struct {
 	Metadata Metadata       `json:"metadata,omitempty"`
 }
 
+// SparsePaginatedResponse is a paginated response whose servers only contain the requested fields
+type SparsePaginatedResponse struct {
+	Data     []any    `json:"servers"`
+	Metadata Metadata `json:"metadata,omitempty"`
+}
+
 // Metadata contains pagination metadata
 type Metadata struct {
 	NextCursor string `json:"next_cursor,omitempty"`
 	Count      int    `json:"count,omitempty"`
-	Total      int    `json:"total,omitempty"`
+	// Total is the number of items across all pages, if requested
+	Total *int `json:"total,omitempty"`
 }
 
 // ServersHandler returns a handler for listing registry items
-func ServersHandler(registry service.RegistryService) http.HandlerFunc {
+func ServersHandler(cfg *config.Config, registry service.RegistryService) http.HandlerFunc {
+	cursors := pagination.NewCodec([]byte(cfg.CursorSecret))
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			problem.MethodNotAllowed(w, r)
+			return
+		}
+
+		sort := database.SortOrder(r.URL.Query().Get("sort"))
+		if sort != database.SortByID && !slices.Contains(database.SortOrders, sort) {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter,
+				"Invalid sort parameter: must be one of name, release_date, -release_date")
 			return
 		}
 
-		// Parse cursor and limit from query parameters
-		cursor := r.URL.Query().Get("cursor")
-		if cursor != "" {
-			_, err := uuid.Parse(cursor)
+		includeTotal := false
+		if v := r.URL.Query().Get("include_total"); v != "" {
+			var err error
+			if includeTotal, err = strconv.ParseBool(v); err != nil {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid include_total parameter")
+				return
+			}
+		}
+
+		// Parse cursor and limit from query parameters. A cursor is only accepted by the listing
+		// it was issued for.
+		filterHash := pagination.FilterHash(map[string]string{"sort": string(sort)})
+		encodeCursor := func(pos database.Position) string { return cursors.Encode(pos, filterHash) }
+		var after *database.Position
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			pos, err := cursors.Decode(cursor, filterHash)
 			if err != nil {
-				http.Error(w, "Invalid cursor parameter", http.StatusBadRequest)
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidCursor, "Invalid cursor parameter: "+err.Error())
 				return
 			}
+			after = &pos
 		}
 		limitStr := r.URL.Query().Get("limit")
 
@@ -50,13 +86,13 @@ func ServersHandler(registry service.RegistryService) http.HandlerFunc {
 		if limitStr != "" {
 			parsedLimit, err := strconv.Atoi(limitStr)
 			if err != nil {
-				http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidLimit, "Invalid limit parameter")
 				return
 			}
 
 			// Check if limit is within reasonable bounds
 			if parsedLimit <= 0 {
-				http.Error(w, "Limit must be greater than 0", http.StatusBadRequest)
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidLimit, "Limit must be greater than 0")
 				return
 			}
 
@@ -68,39 +104,25 @@ func ServersHandler(registry service.RegistryService) http.HandlerFunc {
 			}
 		}
 
-		// Use the GetAll method to get paginated results
-		registries, nextCursor, err := registry.List(cursor, limit)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-
-		// Create paginated response
-		response := PaginatedResponse{
-			Data: registries,
-		}
-
-		// Add metadata if there's a next cursor
-		if nextCursor != "" {
-			response.Metadata = Metadata{
-				NextCursor: nextCursor,
-				Count:      len(registries),
-			}
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(response); err != nil {
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		fields, ok := parseFields(w, r)
+		if !ok {
 			return
 		}
+		writeServerList(w, r, cfg, registry, encodeCursor, database.ListOptions{
+			After:        after,
+			Limit:        limit,
+			Sort:         sort,
+			IncludeTotal: includeTotal,
+			Fields:       fields,
+		})
 	}
 }
 
 // ServersDetailHandler returns a handler for getting details of a specific server by ID
-func ServersDetailHandler(registry service.RegistryService) http.HandlerFunc {
+func ServersDetailHandler(cfg *config.Config, registry service.RegistryService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			problem.MethodNotAllowed(w, r)
 			return
 		}
 
@@ -110,25 +132,99 @@ func ServersDetailHandler(registry service.RegistryService) http.HandlerFunc {
 		// Validate that the ID is a valid UUID
 		_, err := uuid.Parse(id)
 		if err != nil {
-			http.Error(w, "Invalid server ID format", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidID, "Invalid server ID format")
+			return
+		}
+
+		fields, ok := parseFields(w, r)
+		if !ok {
 			return
 		}
 
 		// Get the server details from the registry service
-		serverDetail, err := registry.GetByID(id)
+		var serverDetail *model.ServerDetail
+		if len(fields) > 0 {
+			serverDetail, err = registry.GetByIDProjected(r.Context(), id, fields)
+		} else {
+			serverDetail, err = registry.GetByID(r.Context(), id)
+		}
 		if err != nil {
-			if err.Error() == "record not found" {
-				http.Error(w, "Server not found", http.StatusNotFound)
+			if errors.Is(err, database.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeNotFound, "Server not found")
 				return
 			}
-			http.Error(w, "Error retrieving server details", http.StatusInternalServerError)
+			problem.WriteError(w, r, err, "Error retrieving server details")
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(serverDetail); err != nil {
-			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		var body any = serverDetail
+		if len(fields) > 0 {
+			if body, err = fields.Render(serverDetail); err != nil {
+				problem.WriteError(w, r, err, "Error rendering server details")
+				return
+			}
+		}
+
+		writeCacheableJSON(w, r, body, cfg.CacheControlServerDetail, releaseTime(serverDetail.VersionDetail.ReleaseDate))
+	}
+}
+
+// parseFields parses the fields query parameter, writing a problem response if it names a field
+// that servers don't have
+func parseFields(w http.ResponseWriter, r *http.Request) (projection.Projection, bool) {
+	fields, err := projection.Parse(r.URL.Query().Get("fields"))
+	if err != nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid fields parameter: "+err.Error())
+		return nil, false
+	}
+	return fields, true
+}
+
+// writeServerList writes a page of servers listed with opts, with the next cursor created by
+// encodeCursor. Servers only contain the requested fields if opts.Fields is set.
+func writeServerList(
+	w http.ResponseWriter, r *http.Request, cfg *config.Config, registry service.RegistryService,
+	encodeCursor func(database.Position) string, opts database.ListOptions,
+) {
+	result, err := registry.ListWithOptions(r.Context(), opts)
+	if err != nil {
+		problem.WriteError(w, r, err, "Error retrieving servers")
+		return
+	}
+
+	var metadata Metadata
+	if result.Next != nil {
+		metadata.NextCursor = encodeCursor(*result.Next)
+		metadata.Count = len(result.Servers)
+	}
+	if opts.IncludeTotal {
+		metadata.Total = &result.Total
+	}
+
+	// The page is as fresh as its most recently released entry
+	var lastModified time.Time
+	for _, server := range result.Servers {
+		if t := releaseTime(server.VersionDetail.ReleaseDate); t.After(lastModified) {
+			lastModified = t
+		}
+	}
+
+	if len(opts.Fields) == 0 {
+		response := PaginatedResponse{Data: make([]model.Server, len(result.Servers)), Metadata: metadata}
+		for i, server := range result.Servers {
+			response.Data[i] = server.Server
+		}
+		writeCacheableJSON(w, r, response, cfg.CacheControlServerList, lastModified)
+		return
+	}
+
+	response := SparsePaginatedResponse{Data: make([]any, len(result.Servers)), Metadata: metadata}
+	for i, server := range result.Servers {
+		if response.Data[i], err = opts.Fields.Render(server); err != nil {
+			problem.WriteError(w, r, err, "Error rendering servers")
 			return
 		}
 	}
+
+	writeCacheableJSON(w, r, response, cfg.CacheControlServerList, lastModified)
 }
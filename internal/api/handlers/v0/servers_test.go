@@ -4,15 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/google/uuid"
 	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
+	"github.com/modelcontextprotocol/registry/internal/audit"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/pagination"
+	"github.com/modelcontextprotocol/registry/internal/projection"
+	"github.com/modelcontextprotocol/registry/internal/service"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
 )
 
 func TestServersHandler(t *testing.T) {
@@ -62,7 +69,7 @@ func TestServersHandler(t *testing.T) {
 						},
 					},
 				}
-				registry.Mock.On("List", "", 30).Return(servers, "", nil)
+				registry.Mock.On("ListWithOptions", database.ListOptions{Limit: 30}).Return(serverListResult(servers, nil), nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedServers: []model.Server{
@@ -99,9 +106,11 @@ func TestServersHandler(t *testing.T) {
 			},
 		},
 		{
-			name:        "successful list with cursor and limit",
-			method:      http.MethodGet,
-			queryParams: "?cursor=550e8400-e29b-41d4-a716-446655440000" + "&limit=10",
+			name:   "successful list with cursor and limit",
+			method: http.MethodGet,
+			queryParams: "?cursor=" + pagination.NewCodec(nil).Encode(
+				database.Position{ID: "550e8400-e29b-41d4-a716-446655440000"}, pagination.FilterHash(nil),
+			) + "&limit=10",
 			setupMocks: func(registry *MockRegistryService) {
 				servers := []model.Server{
 					{
@@ -120,8 +129,11 @@ func TestServersHandler(t *testing.T) {
 						},
 					},
 				}
-				nextCursor := uuid.New().String()
-				registry.Mock.On("List", mock.AnythingOfType("string"), 10).Return(servers, nextCursor, nil)
+				next := &database.Position{ID: uuid.New().String()}
+				registry.Mock.On("ListWithOptions", database.ListOptions{
+					After: &database.Position{ID: "550e8400-e29b-41d4-a716-446655440000"},
+					Limit: 10,
+				}).Return(serverListResult(servers, next), nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedServers: []model.Server{
@@ -152,7 +164,7 @@ func TestServersHandler(t *testing.T) {
 			queryParams: "?limit=150",
 			setupMocks: func(registry *MockRegistryService) {
 				servers := []model.Server{}
-				registry.Mock.On("List", "", 100).Return(servers, "", nil)
+				registry.Mock.On("ListWithOptions", database.ListOptions{Limit: 100}).Return(serverListResult(servers, nil), nil)
 			},
 			expectedStatus:  http.StatusOK,
 			expectedServers: []model.Server{},
@@ -165,6 +177,14 @@ func TestServersHandler(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "Invalid cursor parameter",
 		},
+		{
+			name:           "raw server ID cursor",
+			method:         http.MethodGet,
+			queryParams:    "?cursor=550e8400-e29b-41d4-a716-446655440000",
+			setupMocks:     func(_ *MockRegistryService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "malformed cursor",
+		},
 		{
 			name:           "invalid limit parameter - non-numeric",
 			method:         http.MethodGet,
@@ -193,10 +213,11 @@ func TestServersHandler(t *testing.T) {
 			name:   "registry service error",
 			method: http.MethodGet,
 			setupMocks: func(registry *MockRegistryService) {
-				registry.Mock.On("List", "", 30).Return([]model.Server{}, "", errors.New("database connection error"))
+				registry.Mock.On("ListWithOptions", database.ListOptions{Limit: 30}).Return(nil, errors.New("database connection error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedError:  "database connection error",
+			// Internal errors don't leak their message
+			expectedError: `"detail":"Error retrieving servers",`,
 		},
 		{
 			name:           "method not allowed",
@@ -214,7 +235,7 @@ func TestServersHandler(t *testing.T) {
 			tc.setupMocks(mockRegistry)
 
 			// Create handler
-			handler := v0.ServersHandler(mockRegistry)
+			handler := v0.ServersHandler(&config.Config{}, mockRegistry)
 
 			// Create request
 			url := "/v0/servers" + tc.queryParams
@@ -262,6 +283,15 @@ func TestServersHandler(t *testing.T) {
 	}
 }
 
+// serverListResult builds a list result holding servers, with next set if more servers follow
+func serverListResult(servers []model.Server, next *database.Position) *database.ListResult {
+	result := &database.ListResult{Servers: make([]*model.ServerDetail, len(servers)), Next: next}
+	for i := range servers {
+		result.Servers[i] = &model.ServerDetail{Server: servers[i]}
+	}
+	return result
+}
+
 // TestServersHandlerIntegration tests the servers list handler with actual HTTP requests
 func TestServersHandlerIntegration(t *testing.T) {
 	// Create mock registry service
@@ -285,10 +315,10 @@ func TestServersHandlerIntegration(t *testing.T) {
 		},
 	}
 
-	mockRegistry.Mock.On("List", "", 30).Return(servers, "", nil)
+	mockRegistry.Mock.On("ListWithOptions", database.ListOptions{Limit: 30}).Return(serverListResult(servers, nil), nil)
 
 	// Create test server
-	server := httptest.NewServer(v0.ServersHandler(mockRegistry))
+	server := httptest.NewServer(v0.ServersHandler(&config.Config{}, mockRegistry))
 	defer server.Close()
 
 	// Send request to the test server
@@ -354,7 +384,7 @@ func TestServersDetailHandlerIntegration(t *testing.T) {
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		r.SetPathValue("id", serverID)
-		v0.ServersDetailHandler(mockRegistry).ServeHTTP(w, r)
+		v0.ServersDetailHandler(&config.Config{}, mockRegistry).ServeHTTP(w, r)
 	}))
 	defer server.Close()
 
@@ -389,3 +419,236 @@ func TestServersDetailHandlerIntegration(t *testing.T) {
 	// Verify mock expectations
 	mockRegistry.Mock.AssertExpectations(t)
 }
+
+func TestServersDetailHandlerProblemResponses(t *testing.T) {
+	serverID := uuid.New().String()
+
+	testCases := []struct {
+		name           string
+		id             string
+		query          string
+		setupMocks     func(*MockRegistryService)
+		expectedStatus int
+		expectedCode   problem.Code
+	}{
+		{
+			name: "wrapped not found error",
+			id:   serverID,
+			setupMocks: func(registry *MockRegistryService) {
+				registry.Mock.On("GetByID", serverID).Return((*model.ServerDetail)(nil), fmt.Errorf("lookup failed: %w", database.ErrNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   problem.CodeNotFound,
+		},
+		{
+			name:           "invalid server ID",
+			id:             "not-a-uuid",
+			setupMocks:     func(_ *MockRegistryService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   problem.CodeInvalidID,
+		},
+		{
+			name:           "unknown field",
+			id:             serverID,
+			query:          "?fields=name,nmae",
+			setupMocks:     func(_ *MockRegistryService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   problem.CodeInvalidParameter,
+		},
+		{
+			name: "unexpected error",
+			id:   serverID,
+			setupMocks: func(registry *MockRegistryService) {
+				registry.Mock.On("GetByID", serverID).Return((*model.ServerDetail)(nil), errors.New("connection reset"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   problem.CodeInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRegistry := new(MockRegistryService)
+			tc.setupMocks(mockRegistry)
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/v0/servers/"+tc.id+tc.query, nil)
+			assert.NoError(t, err)
+			req.SetPathValue("id", tc.id)
+
+			rr := httptest.NewRecorder()
+			v0.ServersDetailHandler(&config.Config{}, mockRegistry).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			assert.Equal(t, problem.ContentType, rr.Header().Get("Content-Type"))
+
+			var p problem.Problem
+			err = json.NewDecoder(rr.Body).Decode(&p)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, p.Status)
+			assert.Equal(t, tc.expectedCode, p.Code)
+			assert.Equal(t, "/v0/servers/"+tc.id, p.Instance)
+
+			mockRegistry.Mock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServersDetailHandlerCaching(t *testing.T) {
+	serverID := uuid.New().String()
+	cfg := &config.Config{CacheControlServerDetail: "public, max-age=300"}
+
+	mockRegistry := new(MockRegistryService)
+	mockRegistry.Mock.On("GetByID", serverID).Return(&model.ServerDetail{
+		Server: model.Server{
+			ID:   serverID,
+			Name: "cached-server",
+			VersionDetail: model.VersionDetail{
+				Version:     "1.0.0",
+				ReleaseDate: "2025-05-27T12:00:00Z",
+				IsLatest:    true,
+			},
+		},
+	}, nil)
+
+	handler := v0.ServersDetailHandler(cfg, mockRegistry)
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/v0/servers/"+serverID, nil)
+		assert.NoError(t, err)
+		req.SetPathValue("id", serverID)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	first := get("", "")
+	assert.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, "public, max-age=300", first.Header().Get("Cache-Control"))
+	assert.Equal(t, "Tue, 27 May 2025 12:00:00 GMT", first.Header().Get("Last-Modified"))
+
+	// Matching ETag, including in a list and in weak form, yields 304 with no body
+	for _, inm := range []string{etag, `"other", ` + etag, "W/" + etag, "*"} {
+		rr := get("If-None-Match", inm)
+		assert.Equal(t, http.StatusNotModified, rr.Code, inm)
+		assert.Empty(t, rr.Body.String())
+		assert.Equal(t, etag, rr.Header().Get("ETag"))
+	}
+
+	// A different ETag returns the full body
+	rr := get("If-None-Match", `"stale"`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, first.Body.String(), rr.Body.String())
+
+	// If-Modified-Since is honored when If-None-Match is absent
+	assert.Equal(t, http.StatusNotModified, get("If-Modified-Since", "Tue, 27 May 2025 12:00:00 GMT").Code)
+	assert.Equal(t, http.StatusOK, get("If-Modified-Since", "Mon, 26 May 2025 12:00:00 GMT").Code)
+}
+
+func TestServersDetailHandlerFields(t *testing.T) {
+	serverID := uuid.New().String()
+	fields := projection.Projection{"name", "packages.registry_name"}
+
+	mockRegistry := new(MockRegistryService)
+	mockRegistry.Mock.On("GetByIDProjected", serverID, fields).Return(&model.ServerDetail{
+		Server: model.Server{
+			ID:            serverID,
+			Name:          "sparse-server",
+			VersionDetail: model.VersionDetail{ReleaseDate: "2025-05-27T12:00:00Z"},
+		},
+		Packages: []model.Package{{RegistryName: "npm"}, {RegistryName: "docker"}},
+	}, nil)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+		"/v0/servers/"+serverID+"?fields=packages.registry_name,name", nil)
+	assert.NoError(t, err)
+	req.SetPathValue("id", serverID)
+
+	rr := httptest.NewRecorder()
+	v0.ServersDetailHandler(&config.Config{}, mockRegistry).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"name":"sparse-server","packages":[{"registry_name":"npm"},{"registry_name":"docker"}]}`, rr.Body.String())
+	assert.Equal(t, "Tue, 27 May 2025 12:00:00 GMT", rr.Header().Get("Last-Modified"))
+	mockRegistry.Mock.AssertExpectations(t)
+}
+
+func TestServersHandlerSortAndTotal(t *testing.T) {
+	entries := map[string]*model.Server{}
+	for i, name := range []string{"charlie", "alpha", "delta", "bravo"} {
+		id := fmt.Sprintf("550e8400-e29b-41d4-a716-44665544001%d", i)
+		entries[id] = &model.Server{
+			ID:   id,
+			Name: name,
+			VersionDetail: model.VersionDetail{
+				Version:     "1.0.0",
+				ReleaseDate: fmt.Sprintf("2025-05-2%dT00:00:00Z", i),
+				IsLatest:    true,
+			},
+		}
+	}
+	db := database.NewMemoryDB(entries)
+	handler := v0.ServersHandler(&config.Config{}, service.NewRegistryServiceWithDB(db))
+
+	list := func(query string) v0.PaginatedResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/v0/servers?"+query, nil)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var resp v0.PaginatedResponse
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		return resp
+	}
+	names := func(resp v0.PaginatedResponse) []string {
+		var result []string
+		for _, server := range resp.Data {
+			result = append(result, server.Name)
+		}
+		return result
+	}
+
+	first := list("sort=name&limit=2&include_total=true")
+	assert.Equal(t, []string{"alpha", "bravo"}, names(first))
+	assert.NotNil(t, first.Metadata.Total)
+	assert.Equal(t, 4, *first.Metadata.Total)
+
+	// A page is as fresh as its most recently released entry
+	req := httptest.NewRequest(http.MethodGet, "/v0/servers?sort=name&limit=2", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, "Fri, 23 May 2025 00:00:00 GMT", rr.Header().Get("Last-Modified"))
+
+	// Servers published before the cursor position don't shift the next page
+	aardvark := &model.ServerDetail{
+		Server: model.Server{
+			Name:          "aardvark",
+			Repository:    model.Repository{URL: "https://github.com/example/aardvark"},
+			VersionDetail: model.VersionDetail{Version: "1.0.0"},
+		},
+	}
+	assert.NoError(t, db.Publish(context.Background(), aardvark, func(previous *model.ServerDetail) *model.AuditRecord {
+		return audit.PublishRecord(context.Background(), previous, aardvark)
+	}))
+	second := list("sort=name&limit=2&cursor=" + first.Metadata.NextCursor)
+	assert.Equal(t, []string{"charlie", "delta"}, names(second))
+	assert.Nil(t, second.Metadata.Total)
+
+	newest := list("sort=-release_date&limit=2")
+	assert.Equal(t, "aardvark", newest.Data[0].Name)
+	assert.Equal(t, "bravo", newest.Data[1].Name)
+
+	// A cursor can't be used with another order
+	req = httptest.NewRequest(http.MethodGet, "/v0/servers?sort=release_date&cursor="+first.Metadata.NextCursor, nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var p problem.Problem
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&p))
+	assert.Equal(t, problem.CodeInvalidCursor, p.Code)
+}
@@ -3,9 +3,9 @@ package v0
 
 import (
 	"net/http"
-	"os"
-	"path/filepath"
 
+	"github.com/modelcontextprotocol/registry/internal/api/openapi"
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
 	_ "github.com/swaggo/files" // Swagger files needed for embedding
 	httpSwagger "github.com/swaggo/http-swagger"
 )
@@ -21,7 +21,7 @@ func SwaggerHandler() http.HandlerFunc {
 
 		// Serve the Swagger UI
 		handler := httpSwagger.Handler(
-			httpSwagger.URL("/v0/swagger/doc.json"), // The URL to the generated Swagger JSON
+			httpSwagger.URL("/v0/openapi.json"), // The URL to the embedded OpenAPI description
 			httpSwagger.DeepLinking(true),
 		)
 
@@ -30,20 +30,31 @@ func SwaggerHandler() http.HandlerFunc {
 	}
 }
 
-// SwaggerJSONHandler serves the Swagger specification as JSON
-func SwaggerJSONHandler() http.HandlerFunc {
+// OpenAPIYAMLHandler serves the embedded OpenAPI description as YAML
+func OpenAPIYAMLHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Find the project root directory
-		workDir, err := os.Getwd()
-		if err != nil {
-			http.Error(w, "Unable to determine working directory", http.StatusInternalServerError)
+		if r.Method != http.MethodGet {
+			problem.MethodNotAllowed(w, r)
 			return
 		}
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(openapi.YAML())
+	}
+}
 
-		// Path to the swagger YAML file
-		swaggerFilePath := filepath.Join(workDir, "internal", "docs", "swagger.yaml")
-
-		// Serve the file directly with the correct content type
-		http.ServeFile(w, r, swaggerFilePath)
+// OpenAPIJSONHandler serves the embedded OpenAPI description converted to JSON
+func OpenAPIJSONHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			problem.MethodNotAllowed(w, r)
+			return
+		}
+		spec, err := openapi.JSON()
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "Unable to load the API description")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(spec)
 	}
 }
@@ -0,0 +1,136 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/api"
+	"github.com/modelcontextprotocol/registry/internal/api/clientip"
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
+	"github.com/modelcontextprotocol/registry/internal/api/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewareChain(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v0/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			problem.WriteError(w, r, err, "")
+			return
+		}
+		_, _ = w.Write([]byte(requestid.FromContext(r.Context()) + ":" + string(body)))
+	})
+	mux.HandleFunc("/v0/panic", func(_ http.ResponseWriter, _ *http.Request) {
+		panic("boom")
+	})
+
+	handler := api.Chain(mux, api.RequestID(), api.AccessLog(logger), api.Recover(logger), api.LimitBody(8))
+
+	do := func(path, body string, header map[string]string) *httptest.ResponseRecorder {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, path, strings.NewReader(body))
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("generates and propagates request ID", func(t *testing.T) {
+		rr := do("/v0/echo", "hi", nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		id := rr.Header().Get(requestid.Header)
+		assert.Len(t, id, 32)
+		assert.Equal(t, id+":hi", rr.Body.String())
+	})
+
+	t.Run("reuses valid incoming request ID", func(t *testing.T) {
+		rr := do("/v0/echo", "hi", map[string]string{requestid.Header: "abc-123"})
+		assert.Equal(t, "abc-123", rr.Header().Get(requestid.Header))
+		assert.Equal(t, "abc-123:hi", rr.Body.String())
+	})
+
+	t.Run("replaces invalid incoming request ID", func(t *testing.T) {
+		rr := do("/v0/echo", "hi", map[string]string{requestid.Header: "bad id\twith spaces"})
+		assert.NotEqual(t, "bad id\twith spaces", rr.Header().Get(requestid.Header))
+	})
+
+	t.Run("rejects oversized bodies", func(t *testing.T) {
+		rr := do("/v0/echo", "this body is too long", nil)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+		assert.Equal(t, problem.ContentType, rr.Header().Get("Content-Type"))
+	})
+
+	t.Run("recovers from panics with a problem response", func(t *testing.T) {
+		logs.Reset()
+		rr := do("/v0/panic", "", nil)
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+		var p problem.Problem
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&p))
+		assert.Equal(t, problem.CodeInternal, p.Code)
+		assert.Contains(t, logs.String(), "panic in handler")
+	})
+
+	t.Run("writes access log entries", func(t *testing.T) {
+		logs.Reset()
+		rr := do("/v0/echo", "hi", nil)
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+		assert.Equal(t, "request", entry["msg"])
+		assert.Equal(t, "/v0/echo", entry["route"])
+		assert.Equal(t, float64(http.StatusOK), entry["status"])
+		assert.Equal(t, rr.Header().Get(requestid.Header), entry["request_id"])
+		assert.Contains(t, entry, "duration_ms")
+	})
+}
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("2001:db8::/32")}
+	handler := api.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(clientip.FromContext(r.Context())))
+	}), api.ClientIP(trusted))
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		expected     string
+	}{
+		{"direct request", "203.0.113.7:4711", nil, "203.0.113.7"},
+		{"untrusted peer can't claim another address", "203.0.113.7:4711", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"trusted proxy", "10.0.0.2:4711", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"chain of trusted proxies", "10.0.0.2:4711", []string{"198.51.100.1, 10.1.1.1", "10.0.0.3"}, "198.51.100.1"},
+		{"addresses before the first untrusted hop are ignored", "10.0.0.2:4711", []string{"192.0.2.1, 198.51.100.1"}, "198.51.100.1"},
+		{"malformed hop", "10.0.0.2:4711", []string{"198.51.100.1, nonsense"}, "10.0.0.2"},
+		{"trusted proxy without header", "[2001:db8::1]:4711", nil, "2001:db8::1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v0/auth/start", nil)
+			req.RemoteAddr = tc.remoteAddr
+			for _, value := range tc.forwardedFor {
+				req.Header.Add(clientip.ForwardedForHeader, value)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, tc.expected, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Package openapi loads the embedded OpenAPI description of the registry API and validates
+// HTTP traffic against it
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/modelcontextprotocol/registry/docs"
+)
+
+var (
+	jsonOnce sync.Once
+	jsonSpec []byte
+	errJSON  error
+)
+
+// Load parses and validates the embedded OpenAPI document. Every call returns a new copy,
+// so callers may modify it.
+func Load() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(docs.OpenAPI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	return doc, nil
+}
+
+// YAML returns the embedded OpenAPI document as written
+func YAML() []byte {
+	return docs.OpenAPI
+}
+
+// JSON returns the embedded OpenAPI document converted to JSON. The conversion is done once.
+func JSON() ([]byte, error) {
+	jsonOnce.Do(func() {
+		var doc *openapi3.T
+		doc, errJSON = Load()
+		if errJSON != nil {
+			return
+		}
+		jsonSpec, errJSON = doc.MarshalJSON()
+	})
+	return jsonSpec, errJSON
+}
@@ -0,0 +1,184 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
+	"github.com/modelcontextprotocol/registry/internal/api/requestid"
+	"github.com/modelcontextprotocol/registry/internal/config"
+)
+
+// streamingContentTypes are response media types that are passed through as they are written
+// instead of being buffered for validation
+var streamingContentTypes = map[string]bool{
+	"application/x-ndjson": true,
+}
+
+// Middleware validates requests and responses against doc. Requests for paths the document
+// doesn't describe pass through unchecked, as do the bodies of streaming responses.
+//
+// In report mode violations are only logged. In enforce mode invalid requests are rejected with
+// a 400 problem before reaching the handler, and invalid responses are replaced by a 500 problem.
+func Middleware(
+	doc *openapi3.T, mode config.OpenAPIValidationMode, logger *slog.Logger,
+) (func(http.Handler) http.Handler, error) {
+	switch mode {
+	case config.OpenAPIValidationOff, "":
+		return func(next http.Handler) http.Handler { return next }, nil
+	case config.OpenAPIValidationReport, config.OpenAPIValidationEnforce:
+	default:
+		return nil, fmt.Errorf("unsupported OpenAPI validation mode: %s", mode)
+	}
+
+	// Route on the path alone, whatever host the registry is deployed on
+	doc.Servers = openapi3.Servers{{URL: "/"}}
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenAPI router: %w", err)
+	}
+
+	options := &openapi3filter.Options{
+		// Authentication is checked by the handlers themselves
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+		IncludeResponseStatus: true,
+		SkipSettingDefaults:   true,
+	}
+	enforce := mode == config.OpenAPIValidationEnforce
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			requestInput := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+			if err := openapi3filter.ValidateRequest(r.Context(), requestInput); err != nil {
+				if errors.As(err, new(*http.MaxBytesError)) {
+					problem.WriteError(w, r, err, "")
+					return
+				}
+				logViolation(logger, r, "request does not match the OpenAPI description", err)
+				if enforce {
+					problem.Write(w, r, http.StatusBadRequest, problem.CodeBadRequest,
+						"Request does not match the API description: "+err.Error())
+					return
+				}
+			}
+
+			bw := &bufferedWriter{ResponseWriter: w}
+			next.ServeHTTP(bw, r)
+			if bw.streaming {
+				return
+			}
+
+			err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: requestInput,
+				Status:                 bw.Status(),
+				Header:                 bw.Header(),
+				Body:                   io.NopCloser(bytes.NewReader(bw.body.Bytes())),
+				Options:                options,
+			})
+			if err != nil {
+				logViolation(logger, r, "response does not match the OpenAPI description", err)
+				if enforce {
+					header := w.Header()
+					for _, name := range []string{"Cache-Control", "ETag", "Last-Modified", "Content-Length"} {
+						header.Del(name)
+					}
+					problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal,
+						"Response does not match the API description")
+					return
+				}
+			}
+
+			bw.send()
+		})
+	}, nil
+}
+
+// logViolation logs a request or response that doesn't match the OpenAPI description
+func logViolation(logger *slog.Logger, r *http.Request, msg string, err error) {
+	logger.LogAttrs(r.Context(), slog.LevelWarn, msg,
+		slog.String("request_id", requestid.FromContext(r.Context())),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("error", err.Error()),
+	)
+}
+
+// bufferedWriter holds back the response until it has been validated. Streaming responses
+// are passed straight through once their headers are written.
+type bufferedWriter struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	streaming bool
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = status
+
+	mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if streamingContentTypes[mediaType] {
+		w.streaming = true
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.streaming {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.body.Write(b)
+}
+
+// Status returns the response status code, defaulting to 200 if the handler wrote nothing
+func (w *bufferedWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Flush passes through for streaming responses; buffered responses are sent once validated
+func (w *bufferedWriter) Flush() {
+	if w.streaming {
+		_ = http.NewResponseController(w.ResponseWriter).Flush()
+	}
+}
+
+// RecordProblemCode passes the code of a problem written by the handler on to the underlying
+// writer, if it records them
+func (w *bufferedWriter) RecordProblemCode(code problem.Code) {
+	if rec, ok := w.ResponseWriter.(problem.CodeRecorder); ok {
+		rec.RecordProblemCode(code)
+	}
+}
+
+// send writes the buffered response to the underlying writer
+func (w *bufferedWriter) send() {
+	w.ResponseWriter.WriteHeader(w.Status())
+	_, _ = w.ResponseWriter.Write(w.body.Bytes())
+}
@@ -0,0 +1,78 @@
+package openapi_test
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/api/openapi"
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	pingHandler := func(body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, body)
+		})
+	}
+
+	tests := []struct {
+		name    string
+		mode    config.OpenAPIValidationMode
+		path    string
+		handler http.Handler
+		status  int
+	}{
+		{"valid traffic", config.OpenAPIValidationEnforce, "/v0/ping", pingHandler(`{"status":"ok","version":"1"}`), http.StatusOK},
+		{"invalid request", config.OpenAPIValidationEnforce, "/v0/servers?limit=abc", pingHandler(`{}`), http.StatusBadRequest},
+		{"invalid response", config.OpenAPIValidationEnforce, "/v0/ping", pingHandler(`{"status":1}`), http.StatusInternalServerError},
+		{"undocumented path", config.OpenAPIValidationEnforce, "/v0/unknown", pingHandler(`[]`), http.StatusOK},
+		{"report only", config.OpenAPIValidationReport, "/v0/ping", pingHandler(`{"status":1}`), http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := openapi.Load()
+			require.NoError(t, err)
+			validate, err := openapi.Middleware(doc, tc.mode, logger)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			validate(tc.handler).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			assert.Equal(t, tc.status, rr.Code, rr.Body.String())
+		})
+	}
+}
+
+// codeRecorder records the code of the problem written through it
+type codeRecorder struct {
+	*httptest.ResponseRecorder
+	code problem.Code
+}
+
+func (w *codeRecorder) RecordProblemCode(code problem.Code) {
+	w.code = code
+}
+
+func TestMiddlewarePassesOnProblemCodes(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+	validate, err := openapi.Middleware(doc, config.OpenAPIValidationReport, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		problem.Write(w, r, http.StatusServiceUnavailable, problem.CodeUnavailable, "Not ready")
+	})
+	rec := &codeRecorder{ResponseRecorder: httptest.NewRecorder()}
+	validate(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v0/ping", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, problem.CodeUnavailable, rec.code)
+}
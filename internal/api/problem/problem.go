@@ -0,0 +1,174 @@
+// Package problem implements RFC 9457 problem details responses for the API
+package problem
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/lint"
+	"github.com/modelcontextprotocol/registry/internal/pagination"
+)
+
+// ContentType is the media type of problem details responses
+const ContentType = "application/problem+json"
+
+// typePrefix is prepended to a Code to build the problem type URI
+const typePrefix = "urn:mcp-registry:problem:"
+
+// Code is a stable, machine-readable identifier for a class of errors.
+// Clients should branch on the code rather than on the human-readable title or detail.
+type Code string
+
+const (
+	CodeBadRequest             Code = "bad_request"
+	CodeInvalidPayload         Code = "invalid_payload"
+	CodePayloadTooLarge        Code = "payload_too_large"
+	CodeInvalidInput           Code = "invalid_input"
+	CodeInvalidInputDefinition Code = "invalid_input_definition"
+	CodeInvalidID              Code = "invalid_id"
+	CodeInvalidCursor          Code = "invalid_cursor"
+	CodeInvalidLimit           Code = "invalid_limit"
+	CodeInvalidParameter       Code = "invalid_parameter"
+	CodeMethodNotAllowed       Code = "method_not_allowed"
+	CodeNotFound               Code = "not_found"
+	CodeNotInstallable         Code = "not_installable"
+	CodeAuthRequired           Code = "auth_required"
+	CodeAuthFailed             Code = "auth_failed"
+	CodeUnsupportedAuthMethod  Code = "unsupported_auth_method"
+	CodeForbidden              Code = "forbidden"
+	CodeAlreadyExists          Code = "already_exists"
+	CodeVersionNotNewer        Code = "version_not_newer"
+	CodeRateLimited            Code = "rate_limited"
+	CodeUpstreamRateLimited    Code = "upstream_rate_limited"
+	CodeIdempotencyKeyReused   Code = "idempotency_key_reused"
+	CodeIdempotencyKeyInUse    Code = "idempotency_key_in_use"
+	CodeTimeout                Code = "timeout"
+	CodeUnavailable            Code = "unavailable"
+	CodeInternal               Code = "internal_error"
+)
+
+// Problem is an RFC 9457 problem details object with a registry-specific "code" extension member
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     Code   `json:"code"`
+	// Findings locates the problems in a rejected payload, for codes that carry them
+	Findings []lint.Finding `json:"findings,omitempty"`
+}
+
+// New creates a problem with the given status, code and detail.
+// The title is derived from the HTTP status text.
+func New(status int, code Code, detail string) *Problem {
+	return &Problem{
+		Type:   typePrefix + string(code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// FromError maps an error returned by the service, database or auth layers to a problem.
+// Known sentinel errors are matched with errors.Is; anything else becomes an internal error
+// whose detail does not leak the underlying message.
+func FromError(err error) *Problem {
+	switch {
+	case errors.As(err, new(*http.MaxBytesError)):
+		return New(http.StatusRequestEntityTooLarge, CodePayloadTooLarge, "Request body is too large")
+	case errors.Is(err, database.ErrNotFound):
+		return New(http.StatusNotFound, CodeNotFound, err.Error())
+	case errors.Is(err, database.ErrAlreadyExists):
+		return New(http.StatusBadRequest, CodeAlreadyExists, err.Error())
+	case errors.Is(err, database.ErrInvalidVersion):
+		return New(http.StatusBadRequest, CodeVersionNotNewer, err.Error())
+	case errors.Is(err, database.ErrInvalidInput):
+		return New(http.StatusBadRequest, CodeInvalidInput, err.Error())
+	case errors.Is(err, pagination.ErrInvalidCursor):
+		return New(http.StatusBadRequest, CodeInvalidCursor, err.Error())
+	case errors.Is(err, auth.ErrAuthRequired):
+		return New(http.StatusUnauthorized, CodeAuthRequired, err.Error())
+	case errors.Is(err, auth.ErrInvalidRepoRef):
+		return New(http.StatusBadRequest, CodeInvalidInput, err.Error())
+	case errors.Is(err, auth.ErrFlowNotFound):
+		return New(http.StatusNotFound, CodeNotFound, err.Error())
+	case errors.Is(err, auth.ErrUnsupportedAuthMethod):
+		return New(http.StatusBadRequest, CodeUnsupportedAuthMethod, err.Error())
+	case errors.Is(err, auth.ErrTooManyFlows):
+		return New(http.StatusServiceUnavailable, CodeUnavailable, err.Error())
+	case errors.Is(err, auth.ErrGitHubRateLimited):
+		return New(http.StatusServiceUnavailable, CodeUpstreamRateLimited, err.Error())
+	case errors.Is(err, auth.ErrAuthFailed), errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrMissingScope):
+		return New(http.StatusUnauthorized, CodeAuthFailed, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return New(http.StatusGatewayTimeout, CodeTimeout, "The operation timed out")
+	case errors.Is(err, auth.ErrUpstreamUnavailable):
+		return New(http.StatusBadGateway, CodeUnavailable, "The credentials could not be checked; try again later")
+	default:
+		return New(http.StatusInternalServerError, CodeInternal, "An internal error occurred")
+	}
+}
+
+// CodeRecorder is implemented by response writers that want to observe the code of a
+// problem written through them, e.g. to count errors by type
+type CodeRecorder interface {
+	RecordProblemCode(code Code)
+}
+
+// Write sends the problem as an application/problem+json response.
+// The instance member is set to the request path if it isn't already set.
+func (p *Problem) Write(w http.ResponseWriter, r *http.Request) {
+	if p.Instance == "" && r != nil && r.URL != nil {
+		p.Instance = r.URL.Path
+	}
+	if rec, ok := w.(CodeRecorder); ok {
+		rec.RecordProblemCode(p.Code)
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// Write sends a problem response with the given status, code and detail
+func Write(w http.ResponseWriter, r *http.Request, status int, code Code, detail string) {
+	New(status, code, detail).Write(w, r)
+}
+
+// WriteError maps err to a problem with FromError and sends it. If detail is not empty it
+// describes the failed operation and replaces the detail derived from the error: the message of
+// a known error is appended to it, while internal errors and failures of upstream services are
+// only logged, so that their messages don't reach clients.
+func WriteError(w http.ResponseWriter, r *http.Request, err error, detail string) {
+	p := FromError(err)
+	private := p.Code == CodeInternal || errors.Is(err, auth.ErrUpstreamUnavailable)
+	if private {
+		if r != nil && r.URL != nil {
+			log.Printf("%s %s: %s: %v", r.Method, r.URL.Path, cmp.Or(detail, p.Detail), err)
+		} else {
+			log.Printf("%s: %v", cmp.Or(detail, p.Detail), err)
+		}
+	}
+	switch {
+	case detail == "":
+	case private:
+		p.Detail = detail
+	default:
+		p.Detail = detail + ": " + err.Error()
+	}
+	p.Write(w, r)
+}
+
+// MethodNotAllowed sends a method_not_allowed problem
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, http.StatusMethodNotAllowed, CodeMethodNotAllowed, "Method not allowed")
+}
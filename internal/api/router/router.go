@@ -6,15 +6,27 @@ import (
 
 	"github.com/modelcontextprotocol/registry/internal/auth"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/health"
+	"github.com/modelcontextprotocol/registry/internal/idempotency"
+	"github.com/modelcontextprotocol/registry/internal/metrics"
+	"github.com/modelcontextprotocol/registry/internal/ratelimit"
 	"github.com/modelcontextprotocol/registry/internal/service"
 )
 
 // New creates a new router with all API versions registered
-func New(cfg *config.Config, registry service.RegistryService, authService auth.Service) *http.ServeMux {
+func New(
+	cfg *config.Config, registry service.RegistryService, authService auth.Service, limiter *ratelimit.Limiter,
+	idempotencyStore *idempotency.Store, readiness *health.Readiness, seedImport *health.Task,
+) *http.ServeMux {
 	mux := http.NewServeMux()
 
 	// Register routes for all API versions
-	RegisterV0Routes(mux, cfg, registry, authService)
+	RegisterV0Routes(mux, cfg, registry, authService, limiter, idempotencyStore, readiness, seedImport)
+
+	// Serve metrics on the API listener unless a separate metrics address is configured
+	if cfg.MetricsEnabled && cfg.MetricsAddress == "" {
+		mux.Handle("/metrics", metrics.Handler())
+	}
 
 	return mux
 }
@@ -7,21 +7,42 @@ import (
 	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
 	"github.com/modelcontextprotocol/registry/internal/auth"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/health"
+	"github.com/modelcontextprotocol/registry/internal/idempotency"
+	"github.com/modelcontextprotocol/registry/internal/metrics"
+	"github.com/modelcontextprotocol/registry/internal/ratelimit"
 	"github.com/modelcontextprotocol/registry/internal/service"
 )
 
 // RegisterV0Routes registers all v0 API routes to the provided router
 func RegisterV0Routes(
 	mux *http.ServeMux, cfg *config.Config, registry service.RegistryService, authService auth.Service,
+	limiter *ratelimit.Limiter, idempotencyStore *idempotency.Store, readiness *health.Readiness,
+	seedImport *health.Task,
 ) {
 	// Register v0 endpoints
 	mux.HandleFunc("/v0/health", v0.HealthHandler(cfg))
-	mux.HandleFunc("/v0/servers", v0.ServersHandler(registry))
-	mux.HandleFunc("/v0/servers/{id}", v0.ServersDetailHandler(registry))
+	mux.HandleFunc("/v0/health/live", v0.LivenessHandler())
+	mux.HandleFunc("/v0/health/ready", v0.ReadinessHandler(readiness))
+	mux.HandleFunc("/v0/servers", v0.ServersHandler(cfg, registry))
+	mux.HandleFunc("/v0/servers/{id}", v0.ServersDetailHandler(cfg, registry))
+	mux.HandleFunc("/v0/servers:batchGet", v0.BatchGetHandler(cfg, registry))
+	mux.HandleFunc("/v0/servers/{id}/client-config", v0.ClientConfigHandler(cfg, registry))
+	mux.HandleFunc("/v0/export", v0.ExportHandler(registry))
 	mux.HandleFunc("/v0/ping", v0.PingHandler(cfg))
-	mux.HandleFunc("/v0/publish", v0.PublishHandler(registry, authService))
+	mux.HandleFunc("/v0/auth/start", v0.StartAuthHandler(authService, limiter))
+	mux.HandleFunc("/v0/auth/status", v0.CheckAuthStatusHandler(authService))
+	var publish http.Handler = v0.PublishHandler(registry, authService, limiter, idempotencyStore)
+	if cfg.MetricsEnabled {
+		publish = metrics.InstrumentPublish(publish)
+	}
+	// Publishes wait for the seed import, which would otherwise overwrite them
+	mux.Handle("/v0/publish", v0.AfterTask(seedImport, publish))
+	mux.HandleFunc("/v0/admin/audit", v0.AuditLogHandler(cfg, registry, authService))
 
-	// Register Swagger UI routes
+	// Register API description and Swagger UI routes
+	mux.HandleFunc("/v0/openapi.yaml", v0.OpenAPIYAMLHandler())
+	mux.HandleFunc("/v0/openapi.json", v0.OpenAPIJSONHandler())
 	mux.HandleFunc("/v0/swagger/", v0.SwaggerHandler())
-	mux.HandleFunc("/v0/swagger/doc.json", v0.SwaggerJSONHandler())
+	mux.HandleFunc("/v0/swagger/doc.json", v0.OpenAPIJSONHandler())
 }
@@ -2,14 +2,23 @@ package api
 
 import (
 	"context"
+	"errors"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
 	"time"
 
+	"github.com/modelcontextprotocol/registry/internal/api/openapi"
 	"github.com/modelcontextprotocol/registry/internal/api/router"
 	"github.com/modelcontextprotocol/registry/internal/auth"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/health"
+	"github.com/modelcontextprotocol/registry/internal/idempotency"
+	"github.com/modelcontextprotocol/registry/internal/metrics"
+	"github.com/modelcontextprotocol/registry/internal/ratelimit"
 	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/tracing"
 )
 
 // Server represents the HTTP server
@@ -17,37 +26,105 @@ type Server struct {
 	config      *config.Config
 	registry    service.RegistryService
 	authService auth.Service
+	readiness   *health.Readiness
 	router      *http.ServeMux
 	server      *http.Server
+	// metricsServer serves /metrics on its own admin listener when MetricsAddress is set
+	metricsServer *http.Server
 }
 
 // NewServer creates a new HTTP server
-func NewServer(cfg *config.Config, registryService service.RegistryService, authService auth.Service) *Server {
+func NewServer(
+	cfg *config.Config, registryService service.RegistryService, authService auth.Service, limiter *ratelimit.Limiter,
+	idempotencyStore *idempotency.Store, readiness *health.Readiness, seedImport *health.Task,
+) *Server {
 	// Create router with all API versions registered
-	mux := router.New(cfg, registryService, authService)
+	mux := router.New(cfg, registryService, authService, limiter, idempotencyStore, readiness, seedImport)
+
+	// Wrap every route in the same middleware chain
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	middlewares := []Middleware{RequestID(), ClientIP(cfg.TrustedProxies), tracing.Middleware()}
+	if cfg.AccessLog {
+		middlewares = append(middlewares, AccessLog(logger))
+	}
+	if cfg.MetricsEnabled {
+		middlewares = append(middlewares, metrics.Middleware())
+	}
+	middlewares = append(middlewares, Recover(logger), LimitBody(cfg.MaxRequestBodyBytes))
+	if cfg.OpenAPIValidation != config.OpenAPIValidationOff {
+		if validate, err := openAPIValidation(cfg, logger); err != nil {
+			log.Printf("OpenAPI validation disabled: %v", err)
+		} else {
+			middlewares = append(middlewares, validate)
+		}
+	}
 
 	server := &Server{
 		config:      cfg,
 		registry:    registryService,
 		authService: authService,
+		readiness:   readiness,
 		router:      mux,
 		server: &http.Server{
 			Addr:              cfg.ServerAddress,
-			Handler:           mux,
+			Handler:           Chain(mux, middlewares...),
 			ReadHeaderTimeout: 10 * time.Second,
 		},
 	}
 
+	if cfg.MetricsEnabled && cfg.MetricsAddress != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", metrics.Handler())
+		server.metricsServer = &http.Server{
+			Addr:              cfg.MetricsAddress,
+			Handler:           metricsMux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+	}
+
 	return server
 }
 
+// openAPIValidation creates the middleware that checks traffic against the embedded API description
+func openAPIValidation(cfg *config.Config, logger *slog.Logger) (Middleware, error) {
+	doc, err := openapi.Load()
+	if err != nil {
+		return nil, err
+	}
+	return openapi.Middleware(doc, cfg.OpenAPIValidation, logger)
+}
+
 // Start begins listening for incoming HTTP requests
 func (s *Server) Start() error {
+	if s.metricsServer != nil {
+		go func() {
+			log.Printf("Metrics server starting on %s", s.config.MetricsAddress)
+			if err := s.metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("Metrics server failed: %v", err)
+			}
+		}()
+	}
+
 	log.Printf("HTTP server starting on %s", s.config.ServerAddress)
 	return s.server.ListenAndServe()
 }
 
-// Shutdown gracefully shuts down the server
+// Shutdown gracefully shuts down the server. It first reports the server as not ready and
+// waits for ShutdownDelay, giving load balancers time to stop sending new requests.
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.readiness.Shutdown()
+	if s.config.ShutdownDelay > 0 {
+		log.Printf("Waiting %s for traffic to drain", s.config.ShutdownDelay)
+		select {
+		case <-time.After(s.config.ShutdownDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	if s.metricsServer != nil {
+		if err := s.metricsServer.Shutdown(ctx); err != nil {
+			log.Printf("Error shutting down metrics server: %v", err)
+		}
+	}
 	return s.server.Shutdown(ctx)
 }
@@ -0,0 +1,105 @@
+// Package audit provides helpers for recording mutating operations in the registry audit log
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/registry/internal/model"
+)
+
+// SystemActor is the actor recorded for operations not triggered by an authenticated request,
+// such as the seed import performed at startup
+var SystemActor = Actor{Identity: "system"}
+
+// Actor describes who triggered a mutating operation and where the request came from
+type Actor struct {
+	Identity   string
+	AuthMethod model.AuthMethod
+	SourceIP   string
+	RequestID  string
+}
+
+type actorContextKey struct{}
+
+// WithActor returns a copy of ctx carrying the given actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored in ctx, or an anonymous actor if none is set
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorContextKey{}).(Actor); ok {
+		return actor
+	}
+	return Actor{Identity: "anonymous"}
+}
+
+// Digest returns the SHA-256 digest of the JSON encoding of v in "sha256:<hex>" form.
+// A nil value yields an empty digest.
+func Digest(v any) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return DigestBytes(data)
+}
+
+// DigestBytes returns the SHA-256 digest of data in "sha256:<hex>" form
+func DigestBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// NewRecord builds an audit record for an operation performed by the actor stored in ctx.
+// Record IDs are time-ordered so that sorting by ID yields chronological order.
+func NewRecord(ctx context.Context, op model.AuditOperation, target, targetID, beforeDigest, afterDigest string) *model.AuditRecord {
+	actor := ActorFromContext(ctx)
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		id = uuid.New()
+	}
+
+	return &model.AuditRecord{
+		ID:           id.String(),
+		Timestamp:    time.Now().UTC(),
+		Actor:        actor.Identity,
+		AuthMethod:   actor.AuthMethod,
+		SourceIP:     actor.SourceIP,
+		RequestID:    actor.RequestID,
+		Operation:    op,
+		Target:       target,
+		TargetID:     targetID,
+		BeforeDigest: beforeDigest,
+		AfterDigest:  afterDigest,
+	}
+}
+
+// PublishRecord builds the audit record of a publish of published, which superseded previous as
+// the latest version of the server. previous is nil for the first version of a server.
+func PublishRecord(ctx context.Context, previous, published *model.ServerDetail) *model.AuditRecord {
+	return entryRecord(ctx, model.AuditOperationPublish, previous, published)
+}
+
+// SeedImportRecord builds the audit record of a seed import that created or updated imported.
+// previous is the stored entry before the import, or nil if the import created it.
+func SeedImportRecord(ctx context.Context, previous, imported *model.ServerDetail) *model.AuditRecord {
+	return entryRecord(ctx, model.AuditOperationSeedImport, previous, imported)
+}
+
+// entryRecord builds the audit record of an operation that wrote entry, replacing previous
+func entryRecord(ctx context.Context, op model.AuditOperation, previous, entry *model.ServerDetail) *model.AuditRecord {
+	var beforeDigest string
+	if previous != nil {
+		beforeDigest = Digest(previous)
+	}
+	return NewRecord(ctx, op, entry.Name, entry.ID, beforeDigest, Digest(entry))
+}
@@ -0,0 +1,141 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/audit"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRecord(t *testing.T) {
+	actor := audit.Actor{
+		Identity:   "github:octocat",
+		AuthMethod: model.AuthMethodGitHub,
+		SourceIP:   "203.0.113.7",
+		RequestID:  "req-1",
+	}
+	ctx := audit.WithActor(context.Background(), actor)
+
+	first := audit.NewRecord(ctx, model.AuditOperationPublish, "io.github.octocat/server", "id-1", "", "sha256:after")
+	second := audit.NewRecord(ctx, model.AuditOperationPublish, "io.github.octocat/server", "id-2", "", "sha256:after")
+
+	assert.Equal(t, "github:octocat", first.Actor)
+	assert.Equal(t, model.AuthMethodGitHub, first.AuthMethod)
+	assert.Equal(t, "203.0.113.7", first.SourceIP)
+	assert.Equal(t, "req-1", first.RequestID)
+	assert.Equal(t, "id-1", first.TargetID)
+	assert.Less(t, first.ID, second.ID, "record IDs are time-ordered")
+
+	anonymous := audit.NewRecord(context.Background(), model.AuditOperationSeedImport, "seed.json", "", "", "")
+	assert.Equal(t, "anonymous", anonymous.Actor)
+}
+
+func TestDigest(t *testing.T) {
+	assert.Empty(t, audit.Digest(nil))
+	assert.Equal(t, audit.DigestBytes([]byte(`{"a":1}`)), audit.Digest(map[string]int{"a": 1}))
+	assert.NotEqual(t, audit.Digest(map[string]int{"a": 1}), audit.Digest(map[string]int{"a": 2}))
+}
+
+func TestPublishRecord(t *testing.T) {
+	ctx := audit.WithActor(context.Background(), audit.Actor{Identity: "github:octocat"})
+	db := database.NewMemoryDB(map[string]*model.Server{})
+
+	publish := func(version string) *model.ServerDetail {
+		serverDetail := &model.ServerDetail{Server: model.Server{
+			Name:          "io.github.octocat/server",
+			Repository:    model.Repository{URL: "https://github.com/octocat/server"},
+			VersionDetail: model.VersionDetail{Version: version},
+		}}
+		require.NoError(t, db.Publish(ctx, serverDetail, func(previous *model.ServerDetail) *model.AuditRecord {
+			return audit.PublishRecord(ctx, previous, serverDetail)
+		}))
+		return serverDetail
+	}
+
+	v1 := publish("1.0.0")
+	v2 := publish("1.1.0")
+
+	records, _, err := db.ListAuditRecords(ctx, database.AuditFilter{Operation: model.AuditOperationPublish}, "", 10)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	// The first version supersedes nothing; the second supersedes the first as it was published
+	assert.Equal(t, v1.ID, records[1].TargetID)
+	assert.Empty(t, records[1].BeforeDigest)
+	assert.Equal(t, audit.Digest(v1), records[1].AfterDigest)
+
+	assert.Equal(t, v2.ID, records[0].TargetID)
+	assert.Equal(t, audit.Digest(v1), records[0].BeforeDigest)
+	assert.Equal(t, audit.Digest(v2), records[0].AfterDigest)
+	assert.Equal(t, "github:octocat", records[0].Actor)
+
+	stored, err := db.GetByID(ctx, v1.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.VersionDetail.IsLatest)
+}
+
+func TestSeedImportRecord(t *testing.T) {
+	ctx := audit.WithActor(context.Background(), audit.SystemActor)
+	db := database.NewMemoryDB(map[string]*model.Server{})
+
+	importSeed := func(description string) (database.ImportStats, *model.ServerDetail) {
+		server := model.ServerDetail{Server: model.Server{
+			ID:            "id-1",
+			Name:          "io.github.octocat/server",
+			Description:   description,
+			VersionDetail: model.VersionDetail{Version: "1.0.0", IsLatest: true},
+		}}
+		content, err := json.Marshal([]model.ServerDetail{server})
+		require.NoError(t, err)
+		seedPath := filepath.Join(t.TempDir(), "seed.json")
+		require.NoError(t, os.WriteFile(seedPath, content, 0o600))
+
+		stats, err := db.ImportSeed(ctx, seedPath, func(previous, imported *model.ServerDetail) *model.AuditRecord {
+			return audit.SeedImportRecord(ctx, previous, imported)
+		})
+		require.NoError(t, err)
+		return stats, &server
+	}
+
+	_, v1 := importSeed("first")
+	_, _ = importSeed("first")
+	stats, v2 := importSeed("second")
+	assert.Equal(t, 1, stats.Updated)
+
+	// Unchanged entries aren't audited; updates record the entry they replaced
+	records, _, err := db.ListAuditRecords(ctx, database.AuditFilter{Operation: model.AuditOperationSeedImport}, "", 10)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, v1.ID, records[1].TargetID)
+	assert.Empty(t, records[1].BeforeDigest)
+	assert.Equal(t, audit.Digest(v1), records[1].AfterDigest)
+
+	assert.Equal(t, audit.Digest(v1), records[0].BeforeDigest)
+	assert.Equal(t, audit.Digest(v2), records[0].AfterDigest)
+	assert.Equal(t, "system", records[0].Actor)
+}
+
+func TestPublishWithoutAuditRecord(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemoryDB(map[string]*model.Server{})
+
+	serverDetail := &model.ServerDetail{Server: model.Server{
+		Name:          "io.github.octocat/server",
+		Repository:    model.Repository{URL: "https://github.com/octocat/server"},
+		VersionDetail: model.VersionDetail{Version: "1.0.0"},
+	}}
+	err := db.Publish(ctx, serverDetail, func(*model.ServerDetail) *model.AuditRecord { return nil })
+	require.ErrorIs(t, err, database.ErrInvalidInput)
+
+	// Nothing is published unaudited
+	_, err = db.GetByID(ctx, serverDetail.ID)
+	assert.ErrorIs(t, err, database.ErrNotFound)
+}
@@ -4,6 +4,7 @@ package auth
 import (
 	"context"
 	"errors"
+	"slices"
 
 	"github.com/modelcontextprotocol/registry/internal/model"
 )
@@ -13,6 +14,18 @@ var (
 	ErrAuthRequired = errors.New("authentication required")
 	// ErrUnsupportedAuthMethod is returned when an unsupported auth method is used
 	ErrUnsupportedAuthMethod = errors.New("unsupported authentication method")
+	// ErrAuthPending is returned while the user hasn't completed an authentication flow yet
+	ErrAuthPending = errors.New("authorization pending")
+	// ErrFlowNotFound is returned for a status token of an unknown, expired or finished flow
+	ErrFlowNotFound = errors.New("authentication flow not found")
+	// ErrInvalidRepoRef is returned when a flow can't be started for the given server name
+	ErrInvalidRepoRef = errors.New("invalid repo_ref")
+	// ErrTooManyFlows is returned when an instance already holds as many flows as it keeps
+	ErrTooManyFlows = errors.New("too many authentication flows in progress")
+	// ErrUpstreamUnavailable is returned when a service that credentials are checked against
+	// couldn't be reached or gave an unexpected response, so they were neither accepted nor
+	// rejected
+	ErrUpstreamUnavailable = errors.New("upstream service unavailable")
 )
 
 // Service defines the authentication service interface
@@ -23,6 +36,32 @@ type Service interface {
 	// CheckAuthStatus checks the status of an authentication flow using a status token
 	CheckAuthStatus(ctx context.Context, statusToken string) (string, error)
 
-	// ValidateAuth validates the authentication credentials
-	ValidateAuth(ctx context.Context, auth model.Authentication) (bool, error)
+	// ValidateAuth validates the authentication credentials against the namespace in auth.RepoRef
+	// and returns the identity behind them
+	ValidateAuth(ctx context.Context, auth model.Authentication) (*Identity, error)
+
+	// Authenticate resolves the identity behind the credentials without checking namespace ownership
+	Authenticate(ctx context.Context, auth model.Authentication) (*Identity, error)
+}
+
+// Identity describes the principal behind a set of validated credentials
+type Identity struct {
+	Method  model.AuthMethod `json:"method"`
+	Subject string           `json:"subject"`
+}
+
+// String returns the identity in "<method>:<subject>" form, e.g. "github:octocat"
+func (i *Identity) String() string {
+	if i == nil {
+		return ""
+	}
+	return string(i.Method) + ":" + i.Subject
+}
+
+// IsAdmin reports whether the identity is listed in the configured admin identities
+func IsAdmin(adminIdentities []string, identity *Identity) bool {
+	if identity == nil {
+		return false
+	}
+	return slices.Contains(adminIdentities, identity.String())
 }
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds the size of a result cache; results aren't cached while it's full of
+// unexpired entries
+const maxCacheEntries = 10000
+
+// tokenHash identifies a token in caches without keeping the token itself in memory
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// resultCache remembers the results of GitHub lookups. Positive results are kept for ttl;
+// negative results, such as rejected tokens, for negativeTTL. Transient failures aren't cached.
+type resultCache[V any] struct {
+	mu          sync.Mutex
+	entries     map[string]cacheEntry[V]
+	ttl         time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+}
+
+type cacheEntry[V any] struct {
+	value     V
+	err       error
+	expiresAt time.Time
+}
+
+func newResultCache[V any](ttl, negativeTTL time.Duration) *resultCache[V] {
+	return &resultCache[V]{
+		entries:     map[string]cacheEntry[V]{},
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		now:         time.Now,
+	}
+}
+
+// get returns the cached result for key, if there is an unexpired one
+func (c *resultCache[V]) get(key string) (cacheEntry[V], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !c.now().Before(entry.expiresAt) {
+		return cacheEntry[V]{}, false
+	}
+	return entry, true
+}
+
+// put caches a result. negative marks successful lookups with a negative answer, e.g. that a
+// user isn't a member of an organization; errors are negative unless they are transient.
+func (c *resultCache[V]) put(key string, value V, err error, negative bool) {
+	ttl := c.ttl
+	if err != nil || negative {
+		if err != nil && !isDefinitive(err) {
+			return
+		}
+		ttl = c.negativeTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= maxCacheEntries {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCacheEntries {
+			return
+		}
+	}
+	c.entries[key] = cacheEntry[V]{value: value, err: err, expiresAt: now.Add(ttl)}
+}
+
+// isDefinitive reports whether err is GitHub's answer about the credentials, rather than a
+// failure to get one that could succeed on retry
+func isDefinitive(err error) bool {
+	return errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrAuthFailed)
+}
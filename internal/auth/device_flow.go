@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/tracing"
+)
+
+// deviceFlowScope is the scope requested for tokens, which must be able to read org memberships
+const deviceFlowScope = "read:org read:user"
+
+// defaultPollInterval is the polling interval used when GitHub doesn't send one, per RFC 8628
+const defaultPollInterval = 5 * time.Second
+
+// maxFlows bounds the unexpired flows kept by a flow store, since anyone can start flows
+const maxFlows = 10000
+
+// RequestDeviceCode starts a device flow with GitHub
+func (g *GitHubDeviceAuth) RequestDeviceCode(ctx context.Context) (_ *DeviceCodeResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GitHub.RequestDeviceCode")
+	defer func() { tracing.End(span, err) }()
+
+	if g.config.ClientID == "" {
+		return nil, fmt.Errorf("GitHub client ID is not configured")
+	}
+
+	var deviceCode DeviceCodeResponse
+	err = g.postOAuthForm(ctx, "/login/device/code", url.Values{
+		"client_id": {g.config.ClientID},
+		"scope":     {deviceFlowScope},
+	}, &deviceCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request device code: %w", err)
+	}
+	if deviceCode.DeviceCode == "" {
+		return nil, fmt.Errorf("%w: failed to request device code: empty response", ErrUpstreamUnavailable)
+	}
+	return &deviceCode, nil
+}
+
+// ExchangeDeviceCode polls GitHub once for the access token of a device flow. Pending and
+// failed authorizations are reported in the response's Error field.
+func (g *GitHubDeviceAuth) ExchangeDeviceCode(ctx context.Context, deviceCode string) (_ *AccessTokenResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GitHub.ExchangeDeviceCode")
+	defer func() { tracing.End(span, err) }()
+
+	var token AccessTokenResponse
+	err = g.postOAuthForm(ctx, "/login/oauth/access_token", url.Values{
+		"client_id":   {g.config.ClientID},
+		"device_code": {deviceCode},
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+	}, &token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request access token: %w", err)
+	}
+	return &token, nil
+}
+
+// postOAuthForm posts form to an OAuth endpoint and decodes the JSON response into v
+func (g *GitHubDeviceAuth) postOAuthForm(ctx context.Context, path string, form url.Values, v any) error {
+	endpoint := strings.TrimSuffix(g.config.OAuthURL, "/") + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUpstreamUnavailable, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: status %d: %s", ErrUpstreamUnavailable, resp.StatusCode, body)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrUpstreamUnavailable, err)
+	}
+	return nil
+}
+
+// deviceFlow is a device flow started by StartAuthFlow
+type deviceFlow struct {
+	deviceCode string
+	interval   time.Duration
+	nextPoll   time.Time
+	expiresAt  time.Time
+	// polling is set while a request to GitHub is in flight
+	polling bool
+}
+
+// flowStore keeps the device flows of this instance by status token. GitHub is polled at most
+// once per flow interval, however often clients check the status. Flows live in memory, so a
+// status token is only known to the instance that started its flow.
+type flowStore struct {
+	mu    sync.Mutex
+	flows map[string]*deviceFlow
+	now   func() time.Time
+}
+
+func newFlowStore() *flowStore {
+	return &flowStore{flows: map[string]*deviceFlow{}, now: time.Now}
+}
+
+// start stores a flow and returns its status token, or ErrTooManyFlows if the store is full
+func (s *flowStore) start(deviceCode *DeviceCodeResponse) (string, error) {
+	statusToken, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	interval := time.Duration(deviceCode.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for token, flow := range s.flows {
+		if !now.Before(flow.expiresAt) {
+			delete(s.flows, token)
+		}
+	}
+	if len(s.flows) >= maxFlows {
+		return "", ErrTooManyFlows
+	}
+	s.flows[statusToken] = &deviceFlow{
+		deviceCode: deviceCode.DeviceCode,
+		interval:   interval,
+		nextPoll:   now.Add(interval),
+		expiresAt:  now.Add(time.Duration(deviceCode.ExpiresIn) * time.Second),
+	}
+	return statusToken, nil
+}
+
+// poll checks the flow with the given status token, asking GitHub through exchange once the
+// polling interval has passed
+func (s *flowStore) poll(
+	ctx context.Context, statusToken string,
+	exchange func(ctx context.Context, deviceCode string) (*AccessTokenResponse, error),
+) (string, error) {
+	s.mu.Lock()
+	flow, ok := s.flows[statusToken]
+	now := s.now()
+	if ok && !now.Before(flow.expiresAt) {
+		delete(s.flows, statusToken)
+		ok = false
+	}
+	if !ok {
+		s.mu.Unlock()
+		return "", ErrFlowNotFound
+	}
+	if flow.polling || now.Before(flow.nextPoll) {
+		s.mu.Unlock()
+		return "", ErrAuthPending
+	}
+	flow.polling = true
+	deviceCode := flow.deviceCode
+	s.mu.Unlock()
+
+	resp, err := exchange(ctx, deviceCode)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flow.polling = false
+	flow.nextPoll = s.now().Add(flow.interval)
+	if err != nil {
+		return "", err
+	}
+
+	switch resp.Error {
+	case "":
+		if resp.AccessToken == "" {
+			return "", fmt.Errorf("%w: GitHub returned no access token", ErrAuthFailed)
+		}
+		delete(s.flows, statusToken)
+		return resp.AccessToken, nil
+	case "authorization_pending":
+		return "", ErrAuthPending
+	case "slow_down":
+		if resp.Interval > 0 {
+			flow.interval = time.Duration(resp.Interval) * time.Second
+		} else {
+			flow.interval += 5 * time.Second
+		}
+		flow.nextPoll = s.now().Add(flow.interval)
+		return "", ErrAuthPending
+	case "expired_token":
+		delete(s.flows, statusToken)
+		return "", fmt.Errorf("%w: the device code expired", ErrFlowNotFound)
+	case "access_denied":
+		delete(s.flows, statusToken)
+		return "", fmt.Errorf("%w: the user denied the authorization request", ErrAuthFailed)
+	default:
+		delete(s.flows, statusToken)
+		return "", fmt.Errorf("%w: %s", ErrAuthFailed, resp.Error)
+	}
+}
@@ -0,0 +1,99 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDeviceFlowGitHub serves GitHub's device flow endpoints, answering token polls with the
+// given responses in order
+func fakeDeviceFlowGitHub(t *testing.T, polls []map[string]any) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var pollCount atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login/device/code", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "client-id", r.FormValue("client_id"))
+		_ = json.NewEncoder(w).Encode(auth.DeviceCodeResponse{
+			DeviceCode:      "device-code",
+			UserCode:        "WDJB-MJHT",
+			VerificationURI: "https://github.example/login/device",
+			ExpiresIn:       900,
+			Interval:        1,
+		})
+	})
+	mux.HandleFunc("POST /login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "device-code", r.FormValue("device_code"))
+		n := int(pollCount.Add(1))
+		if !assert.LessOrEqual(t, n, len(polls), "unexpected poll") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(polls[n-1])
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &pollCount
+}
+
+func TestDeviceFlow(t *testing.T) {
+	ctx := context.Background()
+	github, polls := fakeDeviceFlowGitHub(t, []map[string]any{
+		{"error": "authorization_pending"},
+		{"error": "slow_down", "interval": 1},
+		{"access_token": "gho_token", "token_type": "bearer"},
+	})
+	service := auth.NewAuthService(&config.Config{GithubClientID: "client-id", GithubOAuthURL: github.URL})
+
+	flowInfo, statusToken, err := service.StartAuthFlow(ctx, model.AuthMethodGitHub, "")
+	require.NoError(t, err)
+	assert.Equal(t, "WDJB-MJHT", flowInfo["user_code"])
+	assert.Equal(t, "900", flowInfo["expires_in"])
+	assert.NotContains(t, flowInfo, "device_code")
+
+	// Checks within the polling interval don't reach GitHub
+	_, err = service.CheckAuthStatus(ctx, statusToken)
+	assert.ErrorIs(t, err, auth.ErrAuthPending)
+	assert.Equal(t, int32(0), polls.Load())
+
+	var token string
+	require.Eventually(t, func() bool {
+		token, err = service.CheckAuthStatus(ctx, statusToken)
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond)
+	assert.Equal(t, "gho_token", token)
+	assert.Equal(t, int32(3), polls.Load())
+
+	// The token is only handed out once
+	_, err = service.CheckAuthStatus(ctx, statusToken)
+	assert.ErrorIs(t, err, auth.ErrFlowNotFound)
+}
+
+func TestDeviceFlowDenied(t *testing.T) {
+	ctx := context.Background()
+	github, _ := fakeDeviceFlowGitHub(t, []map[string]any{{"error": "access_denied"}})
+	service := auth.NewAuthService(&config.Config{GithubClientID: "client-id", GithubOAuthURL: github.URL})
+
+	_, statusToken, err := service.StartAuthFlow(ctx, model.AuthMethodGitHub, "")
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		_, err = service.CheckAuthStatus(ctx, statusToken)
+		return !errors.Is(err, auth.ErrAuthPending)
+	}, 5*time.Second, 100*time.Millisecond)
+	assert.ErrorIs(t, err, auth.ErrAuthFailed)
+
+	_, err = service.CheckAuthStatus(ctx, "unknown")
+	assert.ErrorIs(t, err, auth.ErrFlowNotFound)
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// dnsRecordPrefix is prepended to a domain to get the name of its verification TXT record
+const dnsRecordPrefix = "_mcp-registry."
+
+// TXTResolver looks up the TXT records of a name. *net.Resolver implements it.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// dnsProof publishes challenges in a TXT record of the domain
+type dnsProof struct {
+	resolver TXTResolver
+}
+
+func (p dnsProof) lookup(ctx context.Context, domain string) ([]string, error) {
+	records, err := p.resolver.LookupTXT(ctx, dnsRecordPrefix+domain)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to look up TXT records of %s: %w", ErrUpstreamUnavailable, dnsRecordPrefix+domain, err)
+	}
+	return records, nil
+}
+
+func (p dnsProof) location(domain string) string {
+	return "the TXT record " + dnsRecordPrefix + domain
+}
+
+func (p dnsProof) instructions(domain, value string) map[string]string {
+	return map[string]string{
+		"record_name":  dnsRecordPrefix + domain,
+		"record_value": value,
+	}
+}
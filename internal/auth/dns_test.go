@@ -0,0 +1,163 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResolver serves TXT records from a map and counts lookups
+type fakeResolver struct {
+	mu      sync.Mutex
+	records map[string][]string
+	err     error
+	lookups int
+}
+
+func (r *fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	if r.err != nil {
+		return nil, r.err
+	}
+	records, ok := r.records[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func (r *fakeResolver) set(name string, records ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[name] = records
+}
+
+func newDNSService(resolver *fakeResolver) auth.Service {
+	return auth.NewAuthServiceWithDependencies(&config.Config{
+		DomainVerificationCacheTTL:         time.Hour,
+		DomainVerificationNegativeCacheTTL: time.Hour,
+	}, auth.Dependencies{Resolver: resolver})
+}
+
+func TestNamespaceDomain(t *testing.T) {
+	assert.Equal(t, "example.com", auth.NamespaceDomain("com.example/server"))
+	assert.Equal(t, "api.example.co.uk", auth.NamespaceDomain("uk.co.example.api/server"))
+	assert.Equal(t, "", auth.NamespaceDomain("example/server"))
+	assert.Equal(t, "", auth.NamespaceDomain("com.Example/server"))
+	assert.Equal(t, "", auth.NamespaceDomain("com..example/server"))
+	assert.Equal(t, "", auth.NamespaceDomain("com.-example/server"))
+}
+
+func TestDNSFlow(t *testing.T) {
+	ctx := context.Background()
+	resolver := &fakeResolver{records: map[string][]string{}}
+	service := newDNSService(resolver)
+
+	flowInfo, statusToken, err := service.StartAuthFlow(ctx, model.AuthMethodDNS, "com.example/server")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", flowInfo["domain"])
+	assert.Equal(t, "_mcp-registry.example.com", flowInfo["record_name"])
+	assert.Contains(t, flowInfo["record_value"], "mcp-registry-verification=")
+
+	resolver.set(flowInfo["record_name"], "unrelated", flowInfo["record_value"])
+	token, err := service.CheckAuthStatus(ctx, statusToken)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+
+	// The token proves control of the domain for every server in its namespace
+	identity, err := service.ValidateAuth(ctx, model.Authentication{
+		Method: model.AuthMethodDNS, Token: token, RepoRef: "com.example/other-server",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "dns:example.com", identity.String())
+
+	_, err = service.ValidateAuth(ctx, model.Authentication{
+		Method: model.AuthMethodDNS, Token: token, RepoRef: "org.example/server",
+	})
+	assert.ErrorIs(t, err, auth.ErrAuthFailed)
+
+	_, err = service.ValidateAuth(ctx, model.Authentication{
+		Method: model.AuthMethodDNS, Token: "forged", RepoRef: "com.example/server",
+	})
+	assert.ErrorIs(t, err, auth.ErrAuthFailed)
+}
+
+func TestDNSFlowPending(t *testing.T) {
+	ctx := context.Background()
+	resolver := &fakeResolver{records: map[string][]string{}}
+	service := newDNSService(resolver)
+
+	_, statusToken, err := service.StartAuthFlow(ctx, model.AuthMethodDNS, "com.example/server")
+	require.NoError(t, err)
+
+	_, err = service.CheckAuthStatus(ctx, statusToken)
+	assert.ErrorIs(t, err, auth.ErrAuthPending)
+	// Checks within the interval don't look up the record again
+	_, err = service.CheckAuthStatus(ctx, statusToken)
+	assert.ErrorIs(t, err, auth.ErrAuthPending)
+	assert.Equal(t, 1, resolver.lookups)
+
+	_, _, err = service.StartAuthFlow(ctx, model.AuthMethodDNS, "server")
+	assert.ErrorIs(t, err, auth.ErrInvalidRepoRef)
+}
+
+func TestDNSFlowLimit(t *testing.T) {
+	ctx := context.Background()
+	service := newDNSService(&fakeResolver{records: map[string][]string{}})
+
+	// Flows are started without credentials, so an instance only keeps so many
+	var err error
+	for i := 0; err == nil; i++ {
+		require.LessOrEqual(t, i, 10000, "flows aren't limited")
+		_, _, err = service.StartAuthFlow(ctx, model.AuthMethodDNS, "com.example/server")
+	}
+	assert.ErrorIs(t, err, auth.ErrTooManyFlows)
+}
+
+func TestValidateAuthDNSCaches(t *testing.T) {
+	ctx := context.Background()
+	resolver := &fakeResolver{records: map[string][]string{}}
+	service := newDNSService(resolver)
+
+	flowInfo, statusToken, err := service.StartAuthFlow(ctx, model.AuthMethodDNS, "com.example/server")
+	require.NoError(t, err)
+	resolver.set(flowInfo["record_name"], flowInfo["record_value"])
+	token, err := service.CheckAuthStatus(ctx, statusToken)
+	require.NoError(t, err)
+
+	validate := func() error {
+		_, err := service.ValidateAuth(ctx, model.Authentication{
+			Method: model.AuthMethodDNS, Token: token, RepoRef: "com.example/server",
+		})
+		return err
+	}
+
+	// Verification results are cached until they're due for a recheck
+	resolver.set(flowInfo["record_name"])
+	for range 3 {
+		require.NoError(t, validate())
+	}
+	assert.Equal(t, 1, resolver.lookups)
+
+	// Lookup failures aren't cached
+	resolver.err = errors.New("server misbehaving")
+	unverified := newDNSService(resolver)
+	for range 2 {
+		_, err = unverified.ValidateAuth(ctx, model.Authentication{
+			Method: model.AuthMethodDNS, Token: token, RepoRef: "com.example/server",
+		})
+		assert.ErrorContains(t, err, "server misbehaving")
+	}
+	assert.Equal(t, 3, resolver.lookups)
+}
@@ -0,0 +1,252 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// challengePrefix precedes the challenge in published verification values
+	challengePrefix = "mcp-registry-verification="
+	// domainFlowTTL is how long a publisher has to publish the challenge of a domain flow
+	domainFlowTTL = time.Hour
+	// domainCheckInterval is how often the status of a domain flow looks up the challenge
+	domainCheckInterval = 5 * time.Second
+)
+
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// NamespaceDomain returns the domain that the namespace of a server name is the reverse of, e.g.
+// example.com for com.example/server. It returns "" if the namespace isn't a reversed domain name
+// with at least two labels.
+func NamespaceDomain(serverName string) string {
+	namespace, _, _ := strings.Cut(serverName, "/")
+	labels := strings.Split(namespace, ".")
+	if len(labels) < 2 {
+		return ""
+	}
+	for _, label := range labels {
+		if !dnsLabel.MatchString(label) {
+			return ""
+		}
+	}
+	slices.Reverse(labels)
+	return strings.Join(labels, ".")
+}
+
+// DomainNamespace returns the namespace that is the reverse of domain, e.g. com.example for
+// example.com; it is the inverse of NamespaceDomain
+func DomainNamespace(domain string) string {
+	labels := strings.Split(domain, ".")
+	slices.Reverse(labels)
+	return strings.Join(labels, ".")
+}
+
+// DomainAuthMethod returns the method verifying a token issued for a domain namespace, which is
+// recorded in the token's prefix. Tokens without a known prefix are verified through DNS.
+func DomainAuthMethod(token string) model.AuthMethod {
+	if strings.HasPrefix(token, tokenPrefix(model.AuthMethodHTTP)) {
+		return model.AuthMethodHTTP
+	}
+	return model.AuthMethodDNS
+}
+
+func tokenPrefix(method model.AuthMethod) string {
+	return string(method) + "_"
+}
+
+// challenge derives the value published on a domain from a token, so that the registry can
+// verify tokens without storing them
+func challenge(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return challengePrefix + base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// domainProof is a place where the owner of a domain publishes challenges
+type domainProof interface {
+	// lookup returns the values published on domain. A missing record or file isn't an error.
+	lookup(ctx context.Context, domain string) ([]string, error)
+	// location describes where lookup finds the values of domain
+	location(domain string) string
+	// instructions returns the flow info telling a publisher how to publish value on domain
+	instructions(domain, value string) map[string]string
+}
+
+// domainVerifier checks that a domain publishes the challenge of a token
+type domainVerifier struct {
+	method model.AuthMethod
+	proof  domainProof
+	// verified caches whether a domain publishes a challenge, by domain and challenge
+	verified *resultCache[bool]
+	flows    *domainFlowStore
+}
+
+func newDomainVerifier(method model.AuthMethod, proof domainProof, ttl, negativeTTL time.Duration) *domainVerifier {
+	return &domainVerifier{
+		method:   method,
+		proof:    proof,
+		verified: newResultCache[bool](ttl, negativeTTL),
+		flows:    &domainFlowStore{flows: map[string]*domainFlow{}, now: time.Now},
+	}
+}
+
+// validate checks that the domain of repoRef's namespace publishes the challenge of token
+func (v *domainVerifier) validate(ctx context.Context, token, repoRef string) (*Identity, error) {
+	domain := NamespaceDomain(repoRef)
+	if domain == "" {
+		return nil, fmt.Errorf("%w: %s doesn't have a reverse domain name namespace", ErrAuthFailed, repoRef)
+	}
+	verified, err := v.verify(ctx, domain, token, false)
+	if err != nil {
+		return nil, err
+	}
+	if !verified {
+		return nil, fmt.Errorf("%w: %s doesn't hold the token's challenge", ErrAuthFailed, v.proof.location(domain))
+	}
+	return &Identity{Method: v.method, Subject: domain}, nil
+}
+
+// verify reports whether domain publishes the challenge of token. Results are cached, so that
+// the challenge is looked up again once the cached result expires; fresh skips the cache.
+func (v *domainVerifier) verify(ctx context.Context, domain, token string, fresh bool) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "auth.verifyDomain",
+		attribute.String("auth.method", string(v.method)), attribute.String("auth.domain", domain))
+	defer func() { tracing.End(span, err) }()
+
+	want := challenge(token)
+	key := domain + ":" + want
+	if !fresh {
+		if cached, ok := v.verified.get(key); ok {
+			span.SetAttributes(attribute.Bool("cache.hit", true))
+			return cached.value, cached.err
+		}
+	}
+
+	values, err := v.proof.lookup(ctx, domain)
+	found := slices.ContainsFunc(values, func(value string) bool {
+		return strings.TrimSpace(value) == want
+	})
+	v.verified.put(key, found, err, !found)
+	return found, err
+}
+
+// domainFlow is a flow started by StartAuthFlow for a domain namespace
+type domainFlow struct {
+	domain    string
+	token     string
+	nextCheck time.Time
+	expiresAt time.Time
+	// checking is set while a lookup is in flight
+	checking bool
+}
+
+// domainFlowStore keeps the domain flows of this instance by status token, at most maxFlows
+type domainFlowStore struct {
+	mu    sync.Mutex
+	flows map[string]*domainFlow
+	now   func() time.Time
+}
+
+// start issues a token for the domain of repoRef's namespace and returns the flow info telling
+// the publisher where to publish its challenge, along with the status token. It returns
+// ErrTooManyFlows if the store is full.
+func (v *domainVerifier) start(repoRef string) (map[string]string, string, error) {
+	domain := NamespaceDomain(repoRef)
+	if domain == "" {
+		return nil, "", fmt.Errorf("%w: %q doesn't have a reverse domain name namespace", ErrInvalidRepoRef, repoRef)
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, "", err
+	}
+	token = tokenPrefix(v.method) + token
+	statusToken, err := randomToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	s := v.flows
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for t, flow := range s.flows {
+		if !now.Before(flow.expiresAt) {
+			delete(s.flows, t)
+		}
+	}
+	if len(s.flows) >= maxFlows {
+		return nil, "", ErrTooManyFlows
+	}
+	s.flows[statusToken] = &domainFlow{domain: domain, token: token, nextCheck: now, expiresAt: now.Add(domainFlowTTL)}
+
+	flowInfo := v.proof.instructions(domain, challenge(token))
+	flowInfo["domain"] = domain
+	flowInfo["expires_in"] = strconv.Itoa(int(domainFlowTTL.Seconds()))
+	flowInfo["interval"] = strconv.Itoa(int(domainCheckInterval.Seconds()))
+	return flowInfo, statusToken, nil
+}
+
+// poll checks the flow with the given status token, looking up the challenge at most once per
+// domainCheckInterval. It returns the flow's token once the domain publishes its challenge.
+func (v *domainVerifier) poll(ctx context.Context, statusToken string) (string, error) {
+	s := v.flows
+	s.mu.Lock()
+	flow, ok := s.flows[statusToken]
+	now := s.now()
+	if ok && !now.Before(flow.expiresAt) {
+		delete(s.flows, statusToken)
+		ok = false
+	}
+	if !ok {
+		s.mu.Unlock()
+		return "", ErrFlowNotFound
+	}
+	if flow.checking || now.Before(flow.nextCheck) {
+		s.mu.Unlock()
+		return "", ErrAuthPending
+	}
+	flow.checking = true
+	domain, token := flow.domain, flow.token
+	s.mu.Unlock()
+
+	verified, err := v.verify(ctx, domain, token, true)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flow.checking = false
+	flow.nextCheck = s.now().Add(domainCheckInterval)
+	switch {
+	case err != nil && !isDefinitive(err):
+		return "", err
+	case !verified:
+		// The publisher may still be setting up the record or file
+		return "", ErrAuthPending
+	default:
+		delete(s.flows, statusToken)
+		return token, nil
+	}
+}
+
+// randomToken returns a random, URL-safe token
+func randomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
@@ -10,6 +10,10 @@ import (
 	"io"
 	"net/http"
 	"regexp"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -25,6 +29,18 @@ var (
 type GitHubOAuthConfig struct {
 	ClientID     string
 	ClientSecret string
+	// OAuthURL is the base URL of GitHub's OAuth endpoints, e.g. https://github.com
+	OAuthURL string
+	// APIURL is the base URL of the REST API, e.g. https://api.github.com, or
+	// https://<host>/api/v3 for GitHub Enterprise Server
+	APIURL string
+	// MaxRetries is how often a request failing with a server error or a secondary rate
+	// limit is retried
+	MaxRetries int
+	// CacheTTL is how long token logins and organization memberships are remembered, and
+	// NegativeCacheTTL how long rejected tokens and non-memberships are. Zero disables caching.
+	CacheTTL         time.Duration
+	NegativeCacheTTL time.Duration
 }
 
 // DeviceCodeResponse represents the response from GitHub's device code endpoint
@@ -38,10 +54,13 @@ type DeviceCodeResponse struct {
 
 // AccessTokenResponse represents the response from GitHub's access token endpoint
 type AccessTokenResponse struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	Scope       string `json:"scope"`
-	Error       string `json:"error,omitempty"`
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	Scope            string `json:"scope"`
+	Error            string `json:"error,omitempty"`
+	ErrorDescription string `json:"error_description,omitempty"`
+	// Interval is the new minimum polling interval in seconds, sent with slow_down errors
+	Interval int `json:"interval,omitempty"`
 }
 
 // TokenValidationResponse represents the response from GitHub's token validation endpoint
@@ -58,106 +77,163 @@ type TokenValidationResponse struct {
 // GitHubDeviceAuth provides methods for GitHub device OAuth authentication
 type GitHubDeviceAuth struct {
 	config GitHubOAuthConfig
+	client *http.Client
+
+	// logins caches the logins of tokens by token hash
+	logins *resultCache[string]
+	// memberships caches organization memberships by token hash and organization
+	memberships *resultCache[bool]
+	// rateLimits tracks exhausted rate limits
+	rateLimits *rateLimitTracker
 }
 
-// NewGitHubDeviceAuth creates a new GitHub device auth instance
-func NewGitHubDeviceAuth(config GitHubOAuthConfig) *GitHubDeviceAuth {
+// NewGitHubDeviceAuth creates a new GitHub device auth instance sending requests with client,
+// which should have a timeout
+func NewGitHubDeviceAuth(config GitHubOAuthConfig, client *http.Client) *GitHubDeviceAuth {
 	return &GitHubDeviceAuth{
-		config: config,
+		config:      config,
+		client:      client,
+		logins:      newResultCache[string](config.CacheTTL, config.NegativeCacheTTL),
+		memberships: newResultCache[bool](config.CacheTTL, config.NegativeCacheTTL),
+		rateLimits:  newRateLimitTracker(),
 	}
 }
 
 // ValidateToken validates if a GitHub token has the necessary permissions to access the required repository.
 // It verifies the token owner matches the repository owner or is a member of the owning organization.
 // It also verifies that the token was created for the same ClientID used to set up the authentication.
-// Returns true if valid, false otherwise along with an error explaining the validation failure.
-func (g *GitHubDeviceAuth) ValidateToken(ctx context.Context, token string, requiredRepo string) (bool, error) {
+// Returns the login of the token owner if valid, otherwise an error explaining the validation failure.
+func (g *GitHubDeviceAuth) ValidateToken(ctx context.Context, token string, requiredRepo string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "GitHub.ValidateToken", attribute.String("github.repo", requiredRepo))
+	defer func() { tracing.End(span, err) }()
+
 	// If no repo is required, we can't validate properly
 	if requiredRepo == "" {
-		return false, fmt.Errorf("repository reference is required for token validation")
+		return "", fmt.Errorf("%w: repository reference is required for token validation", ErrAuthFailed)
 	}
 
-	// First, validate that the token is associated with our ClientID
-	tokenReq, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodGet,
-		"https://api.github.com/applications/"+g.config.ClientID+"/token",
-		nil,
-	)
+	login, err := g.Authenticate(ctx, token)
 	if err != nil {
-		return false, err
+		return "", err
 	}
 
-	// The applications endpoint requires basic auth with client ID and secret
-	tokenReq.SetBasicAuth(g.config.ClientID, g.config.ClientSecret)
-	tokenReq.Header.Set("Accept", "application/vnd.github+json")
+	// Extract owner from the required repo
+	owner, _, err := g.ExtractGitHubRepoFromName(requiredRepo)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrAuthFailed, err)
+	}
 
-	// Create request body with the token
-	type tokenCheck struct {
-		AccessToken string `json:"access_token"`
+	// Verify that the authenticated user matches the owner
+	if login != owner {
+		// Check if the user is a member of the organization
+		isMember, err := g.checkOrgMembership(ctx, token, login, owner)
+		if err != nil {
+			return "", fmt.Errorf("failed to check org membership: %s: %w", owner, err)
+		}
+
+		if !isMember {
+			return "", fmt.Errorf(
+				"%w: token belongs to user %s, but repository is owned by %s and user is not a member of the organization",
+				ErrAuthFailed, login, owner)
+		}
+	}
+
+	// If we've reached this point, the token has access the repo and the user matches
+	// the owner or is a member of the owner org
+	return login, nil
+}
+
+// Authenticate verifies that a GitHub token was issued for the configured ClientID
+// and returns the login of the user it belongs to.
+func (g *GitHubDeviceAuth) Authenticate(ctx context.Context, token string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "GitHub.Authenticate")
+	defer func() { tracing.End(span, err) }()
+
+	key := tokenHash(token)
+	if cached, ok := g.logins.get(key); ok {
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		return cached.value, cached.err
 	}
 
-	checkBody, err := json.Marshal(tokenCheck{AccessToken: token})
+	login, err := g.authenticate(ctx, token)
+	g.logins.put(key, login, err, false)
+	return login, err
+}
+
+// authenticate looks up the login of a token with GitHub
+func (g *GitHubDeviceAuth) authenticate(ctx context.Context, token string) (string, error) {
+	// First, validate that the token is associated with our ClientID. The token is sent in the
+	// body of a POST rather than in the URL for security reasons per GitHub API.
+	checkBody, err := json.Marshal(struct {
+		AccessToken string `json:"access_token"`
+	}{AccessToken: token})
 	if err != nil {
-		return false, err
+		return "", err
 	}
 
-	// POST instead of GET for security reasons per GitHub API
-	tokenURL := "https://api.github.com/applications/" + g.config.ClientID + "/token"
-	tokenReq, err = http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, io.NopCloser(bytes.NewReader(checkBody)))
+	tokenReq, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		g.apiURL("/applications/"+g.config.ClientID+"/token"), bytes.NewReader(checkBody))
 	if err != nil {
-		return false, err
+		return "", err
 	}
 
+	// The applications endpoint requires basic auth with client ID and secret
 	tokenReq.SetBasicAuth(g.config.ClientID, g.config.ClientSecret)
 	tokenReq.Header.Set("Accept", "application/vnd.github+json")
 	tokenReq.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	tokenResp, err := client.Do(tokenReq)
+	tokenResp, err := g.do(tokenReq)
 	if err != nil {
-		return false, err
+		return "", err
 	}
 	defer tokenResp.Body.Close()
 
 	// Check response - 200 means token is valid and associated with our app
 	// 404 means token is not associated with our app
-	if tokenResp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("token is not associated with this application (status: %d)", tokenResp.StatusCode)
+	switch tokenResp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusUnprocessableEntity:
+		return "", fmt.Errorf("%w: token is not associated with this application (status: %d)",
+			ErrInvalidToken, tokenResp.StatusCode)
+	default:
+		return "", fmt.Errorf("%w: failed to check token with GitHub: status %d", ErrUpstreamUnavailable, tokenResp.StatusCode)
 	}
 
 	var tokenInfo TokenValidationResponse
 	tokenRespBody, err := io.ReadAll(tokenResp.Body)
 	if err != nil {
-		return false, err
+		return "", fmt.Errorf("%w: failed to read token check response: %w", ErrUpstreamUnavailable, err)
 	}
 
 	if err := json.Unmarshal(tokenRespBody, &tokenInfo); err != nil {
-		return false, err
+		return "", fmt.Errorf("%w: failed to decode token check response: %w", ErrUpstreamUnavailable, err)
 	}
 
 	// Check if there's an error in the response
 	if tokenInfo.Error != "" {
-		return false, fmt.Errorf("token validation error: %s", tokenInfo.Error)
+		return "", fmt.Errorf("%w: token validation error: %s", ErrInvalidToken, tokenInfo.Error)
 	}
 
 	// Get the authenticated user
-	userReq, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/user", nil)
+	userReq, err := http.NewRequestWithContext(ctx, http.MethodGet, g.apiURL("/user"), nil)
 	if err != nil {
-		return false, err
+		return "", err
 	}
 
 	userReq.Header.Set("Accept", "application/vnd.github+json")
 	userReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
-	client = &http.Client{}
-	userResp, err := client.Do(userReq)
+	userResp, err := g.do(userReq)
 	if err != nil {
-		return false, err
+		return "", err
 	}
 	defer userResp.Body.Close()
 
-	if userResp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("failed to get user info: status %d", userResp.StatusCode)
+	switch userResp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return "", fmt.Errorf("%w: failed to get user info: status %d", ErrInvalidToken, userResp.StatusCode)
+	default:
+		return "", fmt.Errorf("%w: failed to get user info: status %d", ErrUpstreamUnavailable, userResp.StatusCode)
 	}
 
 	var userInfo struct {
@@ -166,37 +242,18 @@ func (g *GitHubDeviceAuth) ValidateToken(ctx context.Context, token string, requ
 
 	userBody, err := io.ReadAll(userResp.Body)
 	if err != nil {
-		return false, err
+		return "", fmt.Errorf("%w: failed to read user info: %w", ErrUpstreamUnavailable, err)
 	}
 
 	if err := json.Unmarshal(userBody, &userInfo); err != nil {
-		return false, err
+		return "", fmt.Errorf("%w: failed to decode user info: %w", ErrUpstreamUnavailable, err)
 	}
 
-	// Extract owner from the required repo
-	owner, _, err := g.ExtractGitHubRepoFromName(requiredRepo)
-	if err != nil {
-		return false, err
+	if userInfo.Login == "" {
+		return "", ErrInvalidToken
 	}
 
-	// Verify that the authenticated user matches the owner
-	if userInfo.Login != owner {
-		// Check if the user is a member of the organization
-		isMember, err := g.checkOrgMembership(ctx, token, userInfo.Login, owner)
-		if err != nil {
-			return false, fmt.Errorf("failed to check org membership: %s", owner)
-		}
-
-		if !isMember {
-			return false, fmt.Errorf(
-				"token belongs to user %s, but repository is owned by %s and user is not a member of the organization",
-				userInfo.Login, owner)
-		}
-	}
-
-	// If we've reached this point, the token has access the repo and the user matches
-	// the owner or is a member of the owner org
-	return true, nil
+	return userInfo.Login, nil
 }
 
 func (g *GitHubDeviceAuth) ExtractGitHubRepoFromName(n string) (owner, repo string, err error) {
@@ -220,14 +277,30 @@ func (g *GitHubDeviceAuth) ExtractGitHubRepo(repoURL string) (owner, repo string
 }
 
 // checkOrgMembership checks if a user is a member of an organization
-func (g *GitHubDeviceAuth) checkOrgMembership(ctx context.Context, token, username, org string) (bool, error) {
+func (g *GitHubDeviceAuth) checkOrgMembership(ctx context.Context, token, username, org string) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "GitHub.checkOrgMembership", attribute.String("github.org", org))
+	defer func() { tracing.End(span, err) }()
+
+	// Memberships are keyed by token, since a token only sees the memberships its user may see
+	key := tokenHash(token) + ":" + org
+	if cached, ok := g.memberships.get(key); ok {
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		return cached.value, cached.err
+	}
+
+	isMember, err := g.fetchOrgMembership(ctx, token, username, org)
+	g.memberships.put(key, isMember, err, !isMember)
+	return isMember, err
+}
+
+// fetchOrgMembership asks GitHub whether a user is a member of an organization
+func (g *GitHubDeviceAuth) fetchOrgMembership(ctx context.Context, token, username, org string) (bool, error) {
 	// Create request to check if user is a member of the organization
 	// GitHub API endpoint: GET /orgs/{org}/members/{username}
 	// true if status code is 204 No Content
 	// false if status code is 404 Not Found
 
-	url := fmt.Sprint("https://api.github.com/orgs/", org, "/members/", username)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.apiURL("/orgs/"+org+"/members/"+username), nil)
 	if err != nil {
 		return false, err
 	}
@@ -235,16 +308,18 @@ func (g *GitHubDeviceAuth) checkOrgMembership(ctx context.Context, token, userna
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := g.do(req)
 	if err != nil {
 		return false, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNoContent {
+	switch resp.StatusCode {
+	case http.StatusNoContent:
 		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: unexpected status %d", ErrUpstreamUnavailable, resp.StatusCode)
 	}
-
-	return false, fmt.Errorf("failed to check org membership: status %d", resp.StatusCode)
 }
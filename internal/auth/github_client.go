@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// retryBackoff is the delay before the first retry of a server error; it doubles with every retry
+	retryBackoff = 500 * time.Millisecond
+	// maxRetryDelay bounds how long a request waits for a retry. Longer Retry-After delays, which
+	// secondary rate limits may ask for, are reported to the caller instead of holding the request.
+	maxRetryDelay = 10 * time.Second
+)
+
+// apiURL returns the URL of a REST API path
+func (g *GitHubDeviceAuth) apiURL(path string) string {
+	return strings.TrimSuffix(g.config.APIURL, "/") + path
+}
+
+// ErrGitHubRateLimited is returned when GitHub's rate limit for a token is exhausted
+var ErrGitHubRateLimited = errors.New("GitHub rate limit exceeded")
+
+// RateLimitError reports an exhausted GitHub rate limit and when it resets
+type RateLimitError struct {
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Reset.IsZero() {
+		return ErrGitHubRateLimited.Error()
+	}
+	return fmt.Sprintf("%s, it resets at %s", ErrGitHubRateLimited, e.Reset.UTC().Format(time.RFC3339))
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrGitHubRateLimited
+}
+
+// rateLimitTracker remembers rate limits GitHub reported as exhausted, so that requests that
+// would be rejected anyway fail without reaching GitHub
+type rateLimitTracker struct {
+	mu sync.Mutex
+	// resets maps rate limit buckets to the time they reset
+	resets map[string]time.Time
+	now    func() time.Time
+}
+
+func newRateLimitTracker() *rateLimitTracker {
+	return &rateLimitTracker{resets: map[string]time.Time{}, now: time.Now}
+}
+
+// bucket identifies the rate limit a request counts against, which is per credential
+func bucket(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return hex.EncodeToString(sum[:])
+}
+
+// check returns a RateLimitError if the bucket is known to be exhausted
+func (t *rateLimitTracker) check(bucket string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	reset, ok := t.resets[bucket]
+	if !ok {
+		return nil
+	}
+	if !t.now().Before(reset) {
+		delete(t.resets, bucket)
+		return nil
+	}
+	return &RateLimitError{Reset: reset}
+}
+
+// observe records the rate limit state reported by resp, returning a RateLimitError if resp
+// was rejected because the rate limit is exhausted
+func (t *rateLimitTracker) observe(bucket string, resp *http.Response) error {
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return nil
+	}
+	var reset time.Time
+	if seconds, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(seconds, 0)
+	}
+
+	t.mu.Lock()
+	now := t.now()
+	for b, r := range t.resets {
+		if !now.Before(r) {
+			delete(t.resets, b)
+		}
+	}
+	if reset.After(now) {
+		t.resets[bucket] = reset
+	}
+	t.mu.Unlock()
+
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		return &RateLimitError{Reset: reset}
+	}
+	return nil
+}
+
+// do sends req, retrying server errors and secondary rate limits up to MaxRetries times. It
+// fails with a RateLimitError while the rate limit of the request's credentials is exhausted.
+func (g *GitHubDeviceAuth) do(req *http.Request) (*http.Response, error) {
+	bucket := bucket(req)
+	if err := g.rateLimits.check(bucket); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := g.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUpstreamUnavailable, err)
+		}
+		if err := g.rateLimits.observe(bucket, resp); err != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			return nil, err
+		}
+		if attempt >= g.config.MaxRetries {
+			return resp, nil
+		}
+		delay, retry := retryDelay(resp, attempt)
+		if !retry {
+			return resp, nil
+		}
+
+		// Drain the body so that the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("cannot retry request to %s: body can't be replayed", req.URL.Path)
+			}
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay reports whether resp should be retried, and after how long
+func retryDelay(resp *http.Response, attempt int) (time.Duration, bool) {
+	retryAfter, hasRetryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+
+	var delay time.Duration
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		delay = retryBackoff << attempt
+		if hasRetryAfter {
+			delay = retryAfter
+		}
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		// Secondary rate limits come with a Retry-After header; an exhausted primary rate
+		// limit only resets after up to an hour, so it isn't retried
+		if !hasRetryAfter || resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			return 0, false
+		}
+		delay = retryAfter
+	default:
+		return 0, false
+	}
+	return delay, delay <= maxRetryDelay
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds, as GitHub sends it
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,239 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitHub serves the REST API endpoints used to validate tokens
+type fakeGitHub struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// logins maps the tokens issued for the app to their users
+	logins map[string]string
+	// members maps organizations to their members
+	members map[string][]string
+	// failures are served, in order, before any other response
+	failures []func(w http.ResponseWriter)
+	// requests counts requests by path
+	requests map[string]int
+}
+
+func newFakeGitHub(t *testing.T) *fakeGitHub {
+	t.Helper()
+	f := &fakeGitHub{
+		logins:   map[string]string{},
+		members:  map[string][]string{},
+		requests: map[string]int{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /applications/{client_id}/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		if !ok || clientID != r.PathValue("client_id") || secret != "client-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		if _, ok := f.login(body.AccessToken); !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(auth.TokenValidationResponse{ID: 1, Scopes: []string{"read:org"}})
+	})
+	mux.HandleFunc("GET /user", func(w http.ResponseWriter, r *http.Request) {
+		login, ok := f.login(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"login": login})
+	})
+	mux.HandleFunc("GET /orgs/{org}/members/{username}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if slices.Contains(f.members[r.PathValue("org")], r.PathValue("username")) {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.requests[r.URL.Path]++
+		var failure func(w http.ResponseWriter)
+		if len(f.failures) > 0 {
+			failure, f.failures = f.failures[0], f.failures[1:]
+		}
+		f.mu.Unlock()
+
+		if failure != nil {
+			failure(w)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeGitHub) login(token string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	login, ok := f.logins[token]
+	return login, ok
+}
+
+// service creates an auth service validating tokens against the fake
+func (f *fakeGitHub) service() auth.Service {
+	return auth.NewAuthServiceWithClient(&config.Config{
+		GithubClientID:     "client-id",
+		GithubClientSecret: "client-secret",
+		GithubAPIURL:       f.URL,
+		GithubMaxRetries:   2,
+
+		GithubAuthCacheTTL:         time.Minute,
+		GithubAuthNegativeCacheTTL: time.Minute,
+	}, f.Client())
+}
+
+func TestValidateAuthGitHub(t *testing.T) {
+	github := newFakeGitHub(t)
+	github.logins["owner-token"] = "octocat"
+	github.logins["member-token"] = "hubot"
+	github.logins["outsider-token"] = "mallory"
+	github.members["octo-org"] = []string{"hubot"}
+	service := github.service()
+
+	validate := func(token, name string) (*auth.Identity, error) {
+		return service.ValidateAuth(context.Background(), model.Authentication{
+			Method: model.AuthMethodGitHub, Token: token, RepoRef: name,
+		})
+	}
+
+	identity, err := validate("owner-token", "io.github.octocat/server")
+	require.NoError(t, err)
+	assert.Equal(t, "github:octocat", identity.String())
+
+	identity, err = validate("member-token", "io.github.octo-org/server")
+	require.NoError(t, err)
+	assert.Equal(t, "github:hubot", identity.String())
+
+	_, err = validate("outsider-token", "io.github.octo-org/server")
+	assert.Error(t, err)
+
+	_, err = validate("unknown-token", "io.github.octocat/server")
+	assert.ErrorContains(t, err, "not associated with this application")
+}
+
+func TestValidateAuthGitHubRetries(t *testing.T) {
+	github := newFakeGitHub(t)
+	github.logins["token"] = "octocat"
+	github.failures = []func(w http.ResponseWriter){
+		func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+		func(w http.ResponseWriter) {
+			// A secondary rate limit
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+		},
+	}
+
+	identity, err := github.service().ValidateAuth(context.Background(), model.Authentication{
+		Method: model.AuthMethodGitHub, Token: "token", RepoRef: "io.github.octocat/server",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "github:octocat", identity.String())
+	assert.Equal(t, 3, github.requests["/applications/client-id/token"])
+}
+
+func TestValidateAuthGitHubGivesUp(t *testing.T) {
+	github := newFakeGitHub(t)
+	github.logins["token"] = "octocat"
+	primaryRateLimit := func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "0")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusForbidden)
+	}
+	github.failures = []func(w http.ResponseWriter){primaryRateLimit}
+
+	_, err := github.service().ValidateAuth(context.Background(), model.Authentication{
+		Method: model.AuthMethodGitHub, Token: "token", RepoRef: "io.github.octocat/server",
+	})
+	assert.ErrorIs(t, err, auth.ErrGitHubRateLimited)
+	assert.Equal(t, 1, github.requests["/applications/client-id/token"], "primary rate limits aren't retried")
+}
+
+func TestValidateAuthGitHubCaches(t *testing.T) {
+	github := newFakeGitHub(t)
+	github.logins["member-token"] = "hubot"
+	github.logins["outsider-token"] = "mallory"
+	github.members["octo-org"] = []string{"hubot"}
+	service := github.service()
+
+	validate := func(token, name string) error {
+		_, err := service.ValidateAuth(context.Background(), model.Authentication{
+			Method: model.AuthMethodGitHub, Token: token, RepoRef: name,
+		})
+		return err
+	}
+
+	for range 3 {
+		require.NoError(t, validate("member-token", "io.github.octo-org/server"))
+		assert.ErrorIs(t, validate("outsider-token", "io.github.octo-org/server"), auth.ErrAuthFailed)
+		assert.ErrorIs(t, validate("unknown-token", "io.github.octo-org/server"), auth.ErrInvalidToken)
+	}
+	assert.Equal(t, 3, github.requests["/applications/client-id/token"])
+	assert.Equal(t, 2, github.requests["/user"])
+	assert.Equal(t, 1, github.requests["/orgs/octo-org/members/hubot"])
+	assert.Equal(t, 1, github.requests["/orgs/octo-org/members/mallory"])
+
+	// Memberships are cached per organization
+	require.NoError(t, validate("member-token", "io.github.hubot/server"))
+	assert.Equal(t, 3, github.requests["/applications/client-id/token"])
+}
+
+func TestValidateAuthGitHubRateLimited(t *testing.T) {
+	github := newFakeGitHub(t)
+	github.logins["token"] = "octocat"
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	github.failures = []func(w http.ResponseWriter){
+		func(w http.ResponseWriter) {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+		},
+	}
+	service := github.service()
+
+	for range 2 {
+		_, err := service.ValidateAuth(context.Background(), model.Authentication{
+			Method: model.AuthMethodGitHub, Token: "token", RepoRef: "io.github.octocat/server",
+		})
+		var limited *auth.RateLimitError
+		require.ErrorAs(t, err, &limited)
+		assert.True(t, reset.Equal(limited.Reset))
+	}
+	assert.Equal(t, 1, github.requests["/applications/client-id/token"], "exhausted rate limits aren't retried until they reset")
+}
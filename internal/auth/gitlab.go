@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// gitLabMaintainerAccess is the GitLab access level of maintainers; owners have a higher one
+const gitLabMaintainerAccess = 40
+
+// gitLabNamePrefix precedes the project path in the names of servers published from GitLab
+const gitLabNamePrefix = "io.gitlab."
+
+var gitLabPathSegment = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*$`)
+
+// GitLabConfig configures the GitLab instance tokens are validated against
+type GitLabConfig struct {
+	// URL is the base URL of the GitLab instance, e.g. https://gitlab.com
+	URL string
+}
+
+// GitLabAuth validates GitLab OAuth and personal access tokens
+type GitLabAuth struct {
+	config GitLabConfig
+	client *http.Client
+}
+
+// NewGitLabAuth creates a GitLab validator sending requests with client, which should have a timeout
+func NewGitLabAuth(config GitLabConfig, client *http.Client) *GitLabAuth {
+	return &GitLabAuth{config: config, client: client}
+}
+
+// gitLabUser is the part of a GitLab user used by the registry
+type gitLabUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+}
+
+// ExtractGitLabProjectFromName returns the project path of a server named
+// io.gitlab.<group>/<project>, where the group may have subgroups, e.g. group/subgroup/project
+// for io.gitlab.group/subgroup/project
+func ExtractGitLabProjectFromName(name string) (string, error) {
+	path, ok := strings.CutPrefix(name, gitLabNamePrefix)
+	segments := strings.Split(path, "/")
+	if !ok || len(segments) < 2 {
+		return "", fmt.Errorf("invalid GitLab project name: %s", name)
+	}
+	for _, segment := range segments {
+		if !gitLabPathSegment.MatchString(segment) {
+			return "", fmt.Errorf("invalid GitLab project name: %s", name)
+		}
+	}
+	return path, nil
+}
+
+// ValidateToken checks that the owner of a GitLab token owns, or is a maintainer of, the project
+// that serverName is named after, directly or through one of the project's groups. It returns
+// the username of the token owner.
+func (g *GitLabAuth) ValidateToken(ctx context.Context, token, serverName string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "GitLab.ValidateToken", attribute.String("gitlab.name", serverName))
+	defer func() { tracing.End(span, err) }()
+
+	projectPath, err := ExtractGitLabProjectFromName(serverName)
+	if err != nil {
+		return "", err
+	}
+
+	user, err := g.user(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	var project struct {
+		Namespace struct {
+			Kind     string `json:"kind"`
+			FullPath string `json:"full_path"`
+		} `json:"namespace"`
+	}
+	status, err := g.get(ctx, token, "/projects/"+url.PathEscape(projectPath), &project)
+	if err != nil {
+		return "", err
+	}
+	if status == http.StatusNotFound {
+		return "", fmt.Errorf("%w: GitLab project %s doesn't exist or isn't visible to %s",
+			ErrAuthFailed, projectPath, user.Username)
+	}
+
+	// Projects in a user's personal namespace belong to that user
+	if project.Namespace.Kind == "user" && strings.EqualFold(project.Namespace.FullPath, user.Username) {
+		return user.Username, nil
+	}
+
+	// The members/all endpoint includes members inherited from the project's groups and their
+	// ancestors, which covers nested subgroups
+	var member struct {
+		AccessLevel int `json:"access_level"`
+	}
+	status, err = g.get(ctx, token,
+		"/projects/"+url.PathEscape(projectPath)+"/members/all/"+strconv.Itoa(user.ID), &member)
+	if err != nil {
+		return "", err
+	}
+	if status == http.StatusNotFound || member.AccessLevel < gitLabMaintainerAccess {
+		return "", fmt.Errorf("%w: GitLab user %s is neither an owner nor a maintainer of %s",
+			ErrAuthFailed, user.Username, projectPath)
+	}
+	return user.Username, nil
+}
+
+// Authenticate returns the username of the owner of a GitLab token
+func (g *GitLabAuth) Authenticate(ctx context.Context, token string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "GitLab.Authenticate")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := g.user(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	return user.Username, nil
+}
+
+func (g *GitLabAuth) user(ctx context.Context, token string) (*gitLabUser, error) {
+	var user gitLabUser
+	status, err := g.get(ctx, token, "/user", &user)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound || user.Username == "" {
+		return nil, fmt.Errorf("%w: GitLab returned no user for the token", ErrInvalidToken)
+	}
+	return &user, nil
+}
+
+// get sends an authenticated GET request to a path of the GitLab REST API and decodes a
+// successful response into v. It returns the status of 200 and 404 responses; other statuses
+// are errors.
+func (g *GitLabAuth) get(ctx context.Context, token, path string, v any) (int, error) {
+	endpoint := strings.TrimSuffix(g.config.URL, "/") + "/api/v4" + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	// GitLab accepts both OAuth and personal access tokens as bearer tokens
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to reach GitLab: %w", ErrUpstreamUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			return 0, fmt.Errorf("%w: failed to decode GitLab response: %w", ErrUpstreamUnavailable, err)
+		}
+		return resp.StatusCode, nil
+	case http.StatusNotFound:
+		return resp.StatusCode, nil
+	case http.StatusUnauthorized:
+		return 0, fmt.Errorf("%w: GitLab rejected the token", ErrInvalidToken)
+	case http.StatusForbidden:
+		// Tokens without the read_api or api scope are rejected with 403
+		return 0, fmt.Errorf("%w: the GitLab token needs the read_api scope", ErrMissingScope)
+	default:
+		return 0, fmt.Errorf("%w: unexpected status %d from GitLab", ErrUpstreamUnavailable, resp.StatusCode)
+	}
+}
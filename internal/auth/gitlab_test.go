@@ -0,0 +1,109 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitLab serves the REST API endpoints used to validate tokens
+func fakeGitLab(t *testing.T) *httptest.Server {
+	t.Helper()
+	users := map[string]map[string]any{
+		"alice-token": {"id": 1, "username": "alice"},
+		"bob-token":   {"id": 2, "username": "bob"},
+	}
+	projects := map[string]map[string]any{
+		"alice/tools":                {"kind": "user", "full_path": "alice"},
+		"platform/infra/mcp-servers": {"kind": "group", "full_path": "platform/infra"},
+	}
+	// access levels by project and user ID, including those inherited from ancestor groups
+	members := map[string]map[string]int{
+		"platform/infra/mcp-servers": {"1": 40, "2": 30},
+	}
+
+	mux := http.NewServeMux()
+	user := func(r *http.Request) map[string]any {
+		return users[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+	}
+	mux.HandleFunc("GET /api/v4/user", func(w http.ResponseWriter, r *http.Request) {
+		u := user(r)
+		if u == nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(u)
+	})
+	mux.HandleFunc("GET /api/v4/projects/{project}", func(w http.ResponseWriter, r *http.Request) {
+		namespace, ok := projects[r.PathValue("project")]
+		if !ok || user(r) == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"namespace": namespace})
+	})
+	mux.HandleFunc("GET /api/v4/projects/{project}/members/all/{id}", func(w http.ResponseWriter, r *http.Request) {
+		level, ok := members[r.PathValue("project")][r.PathValue("id")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_level": level})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestValidateAuthGitLab(t *testing.T) {
+	gitlab := fakeGitLab(t)
+	service := auth.NewAuthServiceWithDependencies(&config.Config{GitlabURL: gitlab.URL}, auth.Dependencies{})
+
+	validate := func(token, name string) (*auth.Identity, error) {
+		return service.ValidateAuth(context.Background(), model.Authentication{
+			Method: model.AuthMethodGitLab, Token: token, RepoRef: name,
+		})
+	}
+
+	identity, err := validate("alice-token", "io.gitlab.alice/tools")
+	require.NoError(t, err)
+	assert.Equal(t, "gitlab:alice", identity.String())
+
+	// Maintainers of projects in nested subgroups may publish them
+	identity, err = validate("alice-token", "io.gitlab.platform/infra/mcp-servers")
+	require.NoError(t, err)
+	assert.Equal(t, "gitlab:alice", identity.String())
+
+	_, err = validate("bob-token", "io.gitlab.platform/infra/mcp-servers")
+	assert.ErrorIs(t, err, auth.ErrAuthFailed, "developers may not publish")
+
+	_, err = validate("bob-token", "io.gitlab.alice/tools")
+	assert.ErrorIs(t, err, auth.ErrAuthFailed)
+
+	_, err = validate("alice-token", "io.gitlab.platform/unknown")
+	assert.ErrorIs(t, err, auth.ErrAuthFailed)
+
+	_, err = validate("unknown-token", "io.gitlab.alice/tools")
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func TestExtractGitLabProjectFromName(t *testing.T) {
+	path, err := auth.ExtractGitLabProjectFromName("io.gitlab.group/subgroup/project")
+	require.NoError(t, err)
+	assert.Equal(t, "group/subgroup/project", path)
+
+	for _, name := range []string{"io.gitlab.group", "io.gitlab.group//project", "io.gitlab.group/../project", "io.github.owner/repo"} {
+		_, err := auth.ExtractGitLabProjectFromName(name)
+		assert.Error(t, err, name)
+	}
+}
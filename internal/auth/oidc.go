@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/tracing"
+)
+
+const (
+	// oidcLeeway tolerates clock skew between the registry and the token issuer
+	oidcLeeway = time.Minute
+	// jwksMinRefetch bounds how often tokens signed with unknown keys refetch the key set
+	jwksMinRefetch = time.Minute
+	// maxJWKSBytes bounds the size of key sets
+	maxJWKSBytes = 1 << 20
+)
+
+// GitHubOIDCConfig configures the verification of GitHub Actions OIDC tokens
+type GitHubOIDCConfig struct {
+	// Issuer is the expected iss claim
+	Issuer string
+	// JWKSURL serves the keys tokens are signed with
+	JWKSURL string
+	// Audience is the audience tokens must be requested for
+	Audience string
+	// JWKSCacheTTL is how long fetched keys are used before the key set is fetched again
+	JWKSCacheTTL time.Duration
+}
+
+// GitHubOIDCClaims are the claims of a GitHub Actions OIDC token used by the registry
+type GitHubOIDCClaims struct {
+	Issuer          string   `json:"iss"`
+	Audience        audience `json:"aud"`
+	ExpiresAt       int64    `json:"exp"`
+	NotBefore       int64    `json:"nbf"`
+	Repository      string   `json:"repository"`
+	RepositoryOwner string   `json:"repository_owner"`
+}
+
+// audience is an aud claim, which may be a string or an array of strings
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+// GitHubOIDC verifies OIDC tokens issued to GitHub Actions workflows
+type GitHubOIDC struct {
+	config GitHubOIDCConfig
+	keys   *jwksCache
+	now    func() time.Time
+}
+
+// NewGitHubOIDC creates a verifier fetching keys with client
+func NewGitHubOIDC(config GitHubOIDCConfig, client *http.Client) *GitHubOIDC {
+	return &GitHubOIDC{
+		config: config,
+		keys:   &jwksCache{url: config.JWKSURL, ttl: config.JWKSCacheTTL, client: client, now: time.Now},
+		now:    time.Now,
+	}
+}
+
+// IsJWT reports whether token looks like a JSON Web Token rather than an opaque token
+func IsJWT(token string) bool {
+	return strings.Count(token, ".") == 2 && strings.HasPrefix(token, "eyJ")
+}
+
+// Verify checks the signature, issuer, audience and lifetime of a token and returns its claims
+func (o *GitHubOIDC) Verify(ctx context.Context, token string) (_ *GitHubOIDCClaims, err error) {
+	ctx, span := tracing.Start(ctx, "GitHubOIDC.Verify")
+	defer func() { tracing.End(span, err) }()
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed JWT", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed JWT header: %w", ErrInvalidToken, err)
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("%w: unsupported signing algorithm %q", ErrInvalidToken, header.Alg)
+	}
+
+	key, err := o.keys.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed JWT signature", ErrInvalidToken)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("%w: invalid JWT signature", ErrInvalidToken)
+	}
+
+	var claims GitHubOIDCClaims
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed JWT claims: %w", ErrInvalidToken, err)
+	}
+
+	now := o.now()
+	switch {
+	case claims.Issuer != o.config.Issuer:
+		return nil, fmt.Errorf("%w: token was issued by %q", ErrInvalidToken, claims.Issuer)
+	case !slices.Contains(claims.Audience, o.config.Audience):
+		return nil, fmt.Errorf("%w: token wasn't issued for audience %q", ErrInvalidToken, o.config.Audience)
+	case claims.ExpiresAt == 0 || now.Add(-oidcLeeway).After(time.Unix(claims.ExpiresAt, 0)):
+		return nil, fmt.Errorf("%w: token has expired", ErrInvalidToken)
+	case claims.NotBefore != 0 && now.Add(oidcLeeway).Before(time.Unix(claims.NotBefore, 0)):
+		return nil, fmt.Errorf("%w: token isn't valid yet", ErrInvalidToken)
+	case claims.RepositoryOwner == "" || claims.Repository == "":
+		return nil, fmt.Errorf("%w: token has no repository claims", ErrInvalidToken)
+	}
+	return &claims, nil
+}
+
+// ValidateToken verifies a token and checks that it was issued to a workflow of repository,
+// given as <owner>/<repo>. It returns the repository as spelled in the token.
+func (o *GitHubOIDC) ValidateToken(ctx context.Context, token, repository string) (string, error) {
+	claims, err := o.Verify(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(claims.Repository, repository) {
+		return "", fmt.Errorf("%w: token was issued to a workflow of %s, not of %s",
+			ErrAuthFailed, claims.Repository, repository)
+	}
+	return claims.Repository, nil
+}
+
+func decodeJWTPart(part string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// jwksCache keeps the RSA keys of a JSON Web Key Set by key ID
+type jwksCache struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+	now    func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+	// refresh is the fetch in flight, if any, which concurrent lookups wait for
+	refresh *jwksRefresh
+}
+
+// jwksRefresh is a fetch of the key set shared by the lookups that need it
+type jwksRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+// key returns the key with the given ID. The key set is fetched again once it's older than the
+// TTL, or when a token names an unknown key, which happens after the issuer rotates its keys.
+// The fetch happens without holding the lock, and concurrent lookups share it.
+func (c *jwksCache) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	now := c.now()
+	key, ok := c.keys[kid]
+	stale := c.keys == nil || now.Sub(c.fetchedAt) >= c.ttl
+	if ok && !stale {
+		c.mu.Unlock()
+		return key, nil
+	}
+
+	var refresh *jwksRefresh
+	switch {
+	case c.refresh != nil:
+		refresh = c.refresh
+	case stale || now.Sub(c.fetchedAt) >= jwksMinRefetch:
+		refresh = &jwksRefresh{done: make(chan struct{})}
+		c.refresh = refresh
+		// The fetch is shared, so it mustn't fail because this lookup's request was canceled;
+		// the client's timeout bounds it instead
+		go c.refreshKeys(context.WithoutCancel(ctx), refresh)
+	}
+	c.mu.Unlock()
+
+	var fetchErr error
+	if refresh != nil {
+		select {
+		case <-refresh.done:
+			fetchErr = refresh.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// Check again under the lock, since the key set may have changed in the meantime
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key, ok = c.keys[kid]
+	switch {
+	case ok:
+		// This includes a known key while the issuer is unreachable, which keeps working
+		return key, nil
+	case fetchErr != nil:
+		return nil, fetchErr
+	default:
+		return nil, fmt.Errorf("%w: token was signed with unknown key %q", ErrInvalidToken, kid)
+	}
+}
+
+// refreshKeys fetches the key set, stores it if the fetch succeeded and then completes refresh
+func (c *jwksCache) refreshKeys(ctx context.Context, refresh *jwksRefresh) {
+	keys, err := c.fetch(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.keys, c.fetchedAt = keys, c.now()
+	}
+	refresh.err = err
+	c.refresh = nil
+	close(refresh.done)
+}
+
+// fetch downloads the key set, skipping keys other than RSA ones
+func (c *jwksCache) fetch(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch JWKS: %w", ErrUpstreamUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: failed to fetch JWKS: status %d", ErrUpstreamUnavailable, resp.StatusCode)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSBytes)).Decode(&jwks); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode JWKS: %w", ErrUpstreamUnavailable, err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil || len(e) > 4 {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
@@ -0,0 +1,230 @@
+package auth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIssuer = "https://token.actions.githubusercontent.com"
+
+// fakeIssuer signs OIDC tokens and serves its keys as a JWKS
+type fakeIssuer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PrivateKey
+	fetches int
+	// gate, if set, holds key set responses until it is closed
+	gate chan struct{}
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	f := &fakeIssuer{keys: map[string]*rsa.PrivateKey{}}
+	f.addKey(t, "key-1")
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		f.mu.Lock()
+		f.fetches++
+		gate := f.gate
+		f.mu.Unlock()
+		if gate != nil {
+			<-gate
+		}
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		var keys []map[string]string
+		for kid, key := range f.keys {
+			keys = append(keys, map[string]string{
+				"kty": "RSA",
+				"kid": kid,
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeIssuer) addKey(t *testing.T, kid string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys[kid] = key
+}
+
+// sign returns a token signed with the key kid, holding default claims for octo-org/my-server
+// overridden by claims
+func (f *fakeIssuer) sign(t *testing.T, kid string, claims map[string]any) string {
+	t.Helper()
+	payload := map[string]any{
+		"iss":              testIssuer,
+		"aud":              "mcp-registry",
+		"exp":              time.Now().Add(5 * time.Minute).Unix(),
+		"nbf":              time.Now().Add(-time.Minute).Unix(),
+		"repository":       "octo-org/my-server",
+		"repository_owner": "octo-org",
+	}
+	for name, value := range claims {
+		payload[name] = value
+	}
+
+	encode := func(v any) string {
+		raw, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+	signingInput := encode(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid}) + "." + encode(payload)
+
+	f.mu.Lock()
+	key := f.keys[kid]
+	f.mu.Unlock()
+	if key == nil {
+		// Sign with a key the issuer doesn't publish
+		var err error
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+	}
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (f *fakeIssuer) service(jwksCacheTTL time.Duration) auth.Service {
+	return auth.NewAuthServiceWithDependencies(&config.Config{
+		GithubOIDCIssuer:       testIssuer,
+		GithubOIDCJWKSURL:      f.URL,
+		GithubOIDCAudience:     "mcp-registry",
+		GithubOIDCJWKSCacheTTL: jwksCacheTTL,
+	}, auth.Dependencies{GitHubClient: f.Client()})
+}
+
+func TestValidateAuthGitHubOIDC(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	service := issuer.service(time.Hour)
+
+	validate := func(token, name string) (*auth.Identity, error) {
+		return service.ValidateAuth(context.Background(), model.Authentication{
+			Method: model.AuthMethodGitHubOIDC, Token: token, RepoRef: name,
+		})
+	}
+
+	token := issuer.sign(t, "key-1", nil)
+	assert.True(t, auth.IsJWT(token))
+	identity, err := validate(token, "io.github.octo-org/my-server")
+	require.NoError(t, err)
+	assert.Equal(t, "github-oidc:octo-org/my-server", identity.String())
+
+	_, err = validate(token, "io.github.octo-org/other-server")
+	assert.ErrorIs(t, err, auth.ErrAuthFailed)
+
+	testCases := []struct {
+		name   string
+		kid    string
+		claims map[string]any
+	}{
+		{"expired", "key-1", map[string]any{"exp": time.Now().Add(-time.Hour).Unix()}},
+		{"not yet valid", "key-1", map[string]any{"nbf": time.Now().Add(time.Hour).Unix()}},
+		{"other audience", "key-1", map[string]any{"aud": []string{"sts.amazonaws.com"}}},
+		{"other issuer", "key-1", map[string]any{"iss": "https://issuer.example.com"}},
+		{"unpublished key", "key-2", nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := validate(issuer.sign(t, tc.kid, tc.claims), "io.github.octo-org/my-server")
+			assert.ErrorIs(t, err, auth.ErrInvalidToken)
+		})
+	}
+
+	// Keys are cached; an unknown key only refetches the key set once a minute
+	assert.Equal(t, 1, issuer.fetches)
+}
+
+func TestValidateAuthGitHubOIDCKeyRotation(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	// Every validation finds the cached key set expired
+	service := issuer.service(time.Nanosecond)
+
+	validate := func(kid string) error {
+		_, err := service.ValidateAuth(context.Background(), model.Authentication{
+			Method: model.AuthMethodGitHubOIDC, Token: issuer.sign(t, kid, nil), RepoRef: "io.github.octo-org/my-server",
+		})
+		return err
+	}
+
+	require.NoError(t, validate("key-1"))
+	issuer.addKey(t, "key-2")
+	require.NoError(t, validate("key-2"))
+	assert.Equal(t, 2, issuer.fetches)
+
+	// Known keys keep working while the key set can't be fetched
+	issuer.Close()
+	require.NoError(t, validate("key-1"))
+}
+
+func TestValidateAuthGitHubOIDCSharedFetch(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	gate := make(chan struct{})
+	issuer.mu.Lock()
+	issuer.gate = gate
+	issuer.mu.Unlock()
+	service := issuer.service(time.Hour)
+	token := issuer.sign(t, "key-1", nil)
+
+	validate := func(ctx context.Context) error {
+		_, err := service.ValidateAuth(ctx, model.Authentication{
+			Method: model.AuthMethodGitHubOIDC, Token: token, RepoRef: "io.github.octo-org/my-server",
+		})
+		return err
+	}
+
+	first := make(chan error, 1)
+	go func() { first <- validate(context.Background()) }()
+	require.Eventually(t, func() bool {
+		issuer.mu.Lock()
+		defer issuer.mu.Unlock()
+		return issuer.fetches == 1
+	}, time.Second, time.Millisecond)
+
+	// While the key set is being fetched, other lookups wait for that fetch without holding
+	// the cache's lock, so they can give up when their request does
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	second := make(chan error, 1)
+	go func() { second <- validate(ctx) }()
+	select {
+	case err := <-second:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(5 * time.Second):
+		close(gate)
+		t.Fatal("lookup blocked on the key set fetch of another request")
+	}
+
+	close(gate)
+	require.NoError(t, <-first)
+	require.NoError(t, validate(context.Background()))
+	assert.Equal(t, 1, issuer.fetches)
+}
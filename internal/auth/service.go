@@ -2,58 +2,229 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"strconv"
 
 	"github.com/modelcontextprotocol/registry/internal/config"
 	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ServiceImpl implements the Service interface
 type ServiceImpl struct {
 	config     *config.Config
 	githubAuth *GitHubDeviceAuth
+	githubOIDC *GitHubOIDC
+	gitlabAuth *GitLabAuth
+	flows      *flowStore
+	// domains verify namespaces by method
+	domains map[model.AuthMethod]*domainVerifier
+}
+
+// Dependencies are the external services the authentication service talks to. Nil fields use
+// the defaults.
+type Dependencies struct {
+	// GitHubClient sends GitHub requests, including those for OIDC keys, and should have a timeout
+	GitHubClient *http.Client
+	// GitLabClient sends GitLab requests and should have a timeout
+	GitLabClient *http.Client
+	// Resolver looks up DNS verification records
+	Resolver TXTResolver
+	// WellKnownTransport fetches well-known verification files
+	WellKnownTransport http.RoundTripper
 }
 
 // NewAuthService creates a new authentication service
 //
 //nolint:ireturn // Factory function intentionally returns interface for dependency injection
 func NewAuthService(cfg *config.Config) Service {
+	return NewAuthServiceWithDependencies(cfg, Dependencies{})
+}
+
+// NewAuthServiceWithClient creates a new authentication service sending GitHub requests with client
+//
+//nolint:ireturn // Factory function intentionally returns interface for dependency injection
+func NewAuthServiceWithClient(cfg *config.Config, client *http.Client) Service {
+	return NewAuthServiceWithDependencies(cfg, Dependencies{GitHubClient: client})
+}
+
+// NewAuthServiceWithDependencies creates a new authentication service talking to the given services
+//
+//nolint:ireturn // Factory function intentionally returns interface for dependency injection
+func NewAuthServiceWithDependencies(cfg *config.Config, deps Dependencies) Service {
+	if deps.GitHubClient == nil {
+		deps.GitHubClient = &http.Client{Transport: tracing.Transport(nil), Timeout: cfg.GithubTimeout}
+	}
+	if deps.GitLabClient == nil {
+		deps.GitLabClient = &http.Client{Transport: tracing.Transport(nil), Timeout: cfg.GitlabTimeout}
+	}
+	if deps.Resolver == nil {
+		deps.Resolver = net.DefaultResolver
+	}
+
 	githubConfig := GitHubOAuthConfig{
 		ClientID:     cfg.GithubClientID,
 		ClientSecret: cfg.GithubClientSecret,
+		OAuthURL:     cfg.GithubOAuthURL,
+		APIURL:       cfg.GithubAPIURL,
+		MaxRetries:   cfg.GithubMaxRetries,
+
+		CacheTTL:         cfg.GithubAuthCacheTTL,
+		NegativeCacheTTL: cfg.GithubAuthNegativeCacheTTL,
 	}
 
 	return &ServiceImpl{
 		config:     cfg,
-		githubAuth: NewGitHubDeviceAuth(githubConfig),
+		githubAuth: NewGitHubDeviceAuth(githubConfig, deps.GitHubClient),
+		githubOIDC: NewGitHubOIDC(GitHubOIDCConfig{
+			Issuer:       cfg.GithubOIDCIssuer,
+			JWKSURL:      cfg.GithubOIDCJWKSURL,
+			Audience:     cfg.GithubOIDCAudience,
+			JWKSCacheTTL: cfg.GithubOIDCJWKSCacheTTL,
+		}, deps.GitHubClient),
+		gitlabAuth: NewGitLabAuth(GitLabConfig{URL: cfg.GitlabURL}, deps.GitLabClient),
+		flows:      newFlowStore(),
+		domains: map[model.AuthMethod]*domainVerifier{
+			model.AuthMethodDNS: newDomainVerifier(model.AuthMethodDNS, dnsProof{resolver: deps.Resolver},
+				cfg.DomainVerificationCacheTTL, cfg.DomainVerificationNegativeCacheTTL),
+			model.AuthMethodHTTP: newDomainVerifier(model.AuthMethodHTTP,
+				wellKnownProof{client: newWellKnownClient(deps.WellKnownTransport, cfg.WellKnownTimeout)},
+				cfg.DomainVerificationCacheTTL, cfg.DomainVerificationNegativeCacheTTL),
+		},
 	}
 }
 
-func (s *ServiceImpl) StartAuthFlow(_ context.Context, _ model.AuthMethod,
-	_ string) (map[string]string, string, error) {
-	// return not implemented error
-	return nil, "", fmt.Errorf("not implemented")
+// StartAuthFlow starts a GitHub device flow, or a DNS or HTTP flow for the namespace of repoRef.
+// The flow info holds the user code and verification URI, or the TXT record or file to create, to
+// show the user, and the returned status token is passed to CheckAuthStatus to finish it.
+func (s *ServiceImpl) StartAuthFlow(
+	ctx context.Context, method model.AuthMethod, repoRef string,
+) (_ map[string]string, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "auth.StartAuthFlow", attribute.String("auth.method", string(method)))
+	defer func() { tracing.End(span, err) }()
+
+	if domains, ok := s.domains[method]; ok {
+		return domains.start(repoRef)
+	}
+	if method != model.AuthMethodGitHub {
+		return nil, "", ErrUnsupportedAuthMethod
+	}
+
+	deviceCode, err := s.githubAuth.RequestDeviceCode(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	statusToken, err := s.flows.start(deviceCode)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return map[string]string{
+		"user_code":        deviceCode.UserCode,
+		"verification_uri": deviceCode.VerificationURI,
+		"expires_in":       strconv.Itoa(deviceCode.ExpiresIn),
+		"interval":         strconv.Itoa(deviceCode.Interval),
+	}, statusToken, nil
 }
 
-func (s *ServiceImpl) CheckAuthStatus(_ context.Context, _ string) (string, error) {
-	// return not implemented error
-	return "", fmt.Errorf("not implemented")
+// CheckAuthStatus returns the access token of a finished flow, or ErrAuthPending while the user
+// hasn't authorized it yet. A finished flow's status token can't be used again.
+func (s *ServiceImpl) CheckAuthStatus(ctx context.Context, statusToken string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "auth.CheckAuthStatus")
+	defer func() { tracing.End(span, err) }()
+
+	for _, domains := range s.domains {
+		token, err := domains.poll(ctx, statusToken)
+		if !errors.Is(err, ErrFlowNotFound) {
+			return token, err
+		}
+	}
+	return s.flows.poll(ctx, statusToken, s.githubAuth.ExchangeDeviceCode)
 }
 
 // ValidateAuth validates authentication credentials
-func (s *ServiceImpl) ValidateAuth(ctx context.Context, auth model.Authentication) (bool, error) {
+func (s *ServiceImpl) ValidateAuth(ctx context.Context, auth model.Authentication) (_ *Identity, err error) {
+	ctx, span := tracing.Start(ctx, "auth.ValidateAuth", attribute.String("auth.method", string(auth.Method)))
+	defer func() { tracing.End(span, err) }()
+
 	// If authentication is required but not provided
 	if auth.Method == "" || auth.Method == model.AuthMethodNone {
-		return false, ErrAuthRequired
+		return nil, ErrAuthRequired
 	}
 
 	switch auth.Method {
 	case model.AuthMethodGitHub:
 		// Extract repo reference from the repository URL if it's not provided
-		return s.githubAuth.ValidateToken(ctx, auth.Token, auth.RepoRef)
+		login, err := s.githubAuth.ValidateToken(ctx, auth.Token, auth.RepoRef)
+		if err != nil {
+			return nil, err
+		}
+		return &Identity{Method: model.AuthMethodGitHub, Subject: login}, nil
+	case model.AuthMethodGitHubOIDC:
+		owner, repo, err := s.githubAuth.ExtractGitHubRepoFromName(auth.RepoRef)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrAuthFailed, err)
+		}
+		repository, err := s.githubOIDC.ValidateToken(ctx, auth.Token, owner+"/"+repo)
+		if err != nil {
+			return nil, err
+		}
+		return &Identity{Method: model.AuthMethodGitHubOIDC, Subject: repository}, nil
+	case model.AuthMethodGitLab:
+		username, err := s.gitlabAuth.ValidateToken(ctx, auth.Token, auth.RepoRef)
+		if err != nil {
+			return nil, err
+		}
+		return &Identity{Method: model.AuthMethodGitLab, Subject: username}, nil
+	case model.AuthMethodDNS, model.AuthMethodHTTP:
+		return s.domains[auth.Method].validate(ctx, auth.Token, auth.RepoRef)
+	case model.AuthMethodNone:
+		return nil, ErrAuthRequired
+	default:
+		return nil, ErrUnsupportedAuthMethod
+	}
+}
+
+// Authenticate resolves the identity behind authentication credentials without checking namespace
+// ownership. Domain tokens don't identify their domain, so for domain methods auth.RepoRef must
+// name a server or namespace of the domain to check.
+func (s *ServiceImpl) Authenticate(ctx context.Context, auth model.Authentication) (_ *Identity, err error) {
+	ctx, span := tracing.Start(ctx, "auth.Authenticate", attribute.String("auth.method", string(auth.Method)))
+	defer func() { tracing.End(span, err) }()
+
+	if auth.Method == "" || auth.Method == model.AuthMethodNone || auth.Token == "" {
+		return nil, ErrAuthRequired
+	}
+
+	switch auth.Method {
+	case model.AuthMethodGitHub:
+		login, err := s.githubAuth.Authenticate(ctx, auth.Token)
+		if err != nil {
+			return nil, err
+		}
+		return &Identity{Method: model.AuthMethodGitHub, Subject: login}, nil
+	case model.AuthMethodGitHubOIDC:
+		claims, err := s.githubOIDC.Verify(ctx, auth.Token)
+		if err != nil {
+			return nil, err
+		}
+		return &Identity{Method: model.AuthMethodGitHubOIDC, Subject: claims.Repository}, nil
+	case model.AuthMethodGitLab:
+		username, err := s.gitlabAuth.Authenticate(ctx, auth.Token)
+		if err != nil {
+			return nil, err
+		}
+		return &Identity{Method: model.AuthMethodGitLab, Subject: username}, nil
+	case model.AuthMethodDNS, model.AuthMethodHTTP:
+		return s.domains[auth.Method].validate(ctx, auth.Token, auth.RepoRef)
 	case model.AuthMethodNone:
-		return false, ErrAuthRequired
+		return nil, ErrAuthRequired
 	default:
-		return false, ErrUnsupportedAuthMethod
+		return nil, ErrUnsupportedAuthMethod
 	}
 }
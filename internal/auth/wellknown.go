@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/tracing"
+)
+
+const (
+	// wellKnownPath is the path of the verification file on a domain
+	wellKnownPath = "/.well-known/mcp-registry-auth"
+	// maxWellKnownBytes bounds the size of verification files
+	maxWellKnownBytes = 4096
+)
+
+// wellKnownProof publishes challenges in a file served over HTTPS by the domain
+type wellKnownProof struct {
+	client *http.Client
+}
+
+// newWellKnownClient returns the client fetching verification files. It doesn't follow
+// redirects. Without a transport, it only connects to public addresses, so that namespaces
+// can't be used to probe the registry's network.
+func newWellKnownClient(transport http.RoundTripper, timeout time.Duration) *http.Client {
+	if transport == nil {
+		base := http.DefaultTransport.(*http.Transport).Clone()
+		base.Proxy = nil
+		base.DialContext = (&net.Dialer{Timeout: timeout, Control: dialPublicOnly}).DialContext
+		transport = tracing.Transport(base)
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// dialPublicOnly refuses connections to loopback, private and other non-public addresses
+func dialPublicOnly(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	addr := addrPort.Addr().Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return fmt.Errorf("refusing to connect to non-public address %s", addr)
+	}
+	return nil
+}
+
+func (p wellKnownProof) url(domain string) string {
+	return "https://" + domain + wellKnownPath
+}
+
+func (p wellKnownProof) lookup(ctx context.Context, domain string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url(domain), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/plain")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch %s: %w", ErrUpstreamUnavailable, p.url(domain), err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, nil
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		return nil, fmt.Errorf("%w: %s redirects, but redirects aren't followed", ErrAuthFailed, p.url(domain))
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return nil, fmt.Errorf("%w: failed to fetch %s: status %d", ErrUpstreamUnavailable, p.url(domain), resp.StatusCode)
+	default:
+		return nil, fmt.Errorf("%w: %s returned status %d", ErrAuthFailed, p.url(domain), resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxWellKnownBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to fetch %s: %w", ErrUpstreamUnavailable, p.url(domain), err)
+	}
+	if len(body) > maxWellKnownBytes {
+		return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrAuthFailed, p.url(domain), maxWellKnownBytes)
+	}
+
+	var values []string
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		values = append(values, scanner.Text())
+	}
+	return values, nil
+}
+
+func (p wellKnownProof) location(domain string) string {
+	return p.url(domain)
+}
+
+func (p wellKnownProof) instructions(domain, value string) map[string]string {
+	return map[string]string{
+		"url":          p.url(domain),
+		"file_content": value,
+	}
+}
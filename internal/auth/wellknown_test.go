@@ -0,0 +1,133 @@
+package auth_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWebServer serves well-known files for every domain. Its certificate is valid for
+// example.com and its subdomains.
+type fakeWebServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	handler http.HandlerFunc
+}
+
+func newFakeWebServer(t *testing.T) *fakeWebServer {
+	t.Helper()
+	f := &fakeWebServer{}
+	f.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		handler := f.handler
+		f.mu.Unlock()
+		if handler == nil || r.URL.Path != "/.well-known/mcp-registry-auth" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeWebServer) serve(handler http.HandlerFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handler = handler
+}
+
+// service creates an auth service whose well-known requests all reach the fake
+func (f *fakeWebServer) service() auth.Service {
+	transport := f.Client().Transport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, f.Listener.Addr().String())
+	}
+	return auth.NewAuthServiceWithDependencies(&config.Config{
+		DomainVerificationCacheTTL:         time.Hour,
+		DomainVerificationNegativeCacheTTL: time.Hour,
+		WellKnownTimeout:                   5 * time.Second,
+	}, auth.Dependencies{WellKnownTransport: transport})
+}
+
+// issueWellKnownToken runs an HTTP flow for com.example against the fake and returns its token
+// and the file content proving it, leaving the fake serving the file
+func issueWellKnownToken(t *testing.T, web *fakeWebServer) (string, string) {
+	t.Helper()
+	ctx := context.Background()
+	service := web.service()
+
+	flowInfo, statusToken, err := service.StartAuthFlow(ctx, model.AuthMethodHTTP, "com.example/server")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/.well-known/mcp-registry-auth", flowInfo["url"])
+
+	web.serve(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("# published for the MCP registry\n" + flowInfo["file_content"] + "\n"))
+	})
+	token, err := service.CheckAuthStatus(ctx, statusToken)
+	require.NoError(t, err)
+	return token, flowInfo["file_content"]
+}
+
+func TestWellKnownFlow(t *testing.T) {
+	web := newFakeWebServer(t)
+	token, _ := issueWellKnownToken(t, web)
+	assert.Equal(t, model.AuthMethodHTTP, auth.DomainAuthMethod(token))
+
+	identity, err := web.service().ValidateAuth(context.Background(), model.Authentication{
+		Method: model.AuthMethodHTTP, Token: token, RepoRef: "com.example/other-server",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "http:example.com", identity.String())
+}
+
+func TestWellKnownRejects(t *testing.T) {
+	web := newFakeWebServer(t)
+	token, content := issueWellKnownToken(t, web)
+
+	testCases := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"redirect", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "https://other.example.com/.well-known/mcp-registry-auth", http.StatusFound)
+		}},
+		{"missing file", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNotFound) }},
+		{"forbidden", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusForbidden) }},
+		{"other token", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("mcp-registry-verification=other\n"))
+		}},
+		{"file too large", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(content + "\n" + strings.Repeat("#\n", 4096)))
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			web.serve(tc.handler)
+			_, err := web.service().ValidateAuth(context.Background(), model.Authentication{
+				Method: model.AuthMethodHTTP, Token: token, RepoRef: "com.example/server",
+			})
+			assert.ErrorIs(t, err, auth.ErrAuthFailed)
+		})
+	}
+
+	// Server errors may be transient, so they aren't reported as rejected credentials
+	web.serve(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusServiceUnavailable) })
+	_, err := web.service().ValidateAuth(context.Background(), model.Authentication{
+		Method: model.AuthMethodHTTP, Token: token, RepoRef: "com.example/server",
+	})
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, auth.ErrAuthFailed)
+}
@@ -0,0 +1,303 @@
+// Package clientconfig turns a registry server entry into the configuration block that an MCP
+// client needs to launch or connect to it
+package clientconfig
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/internal/launch"
+	"github.com/modelcontextprotocol/registry/internal/model"
+)
+
+// Client identifies an MCP client whose configuration format is supported
+type Client string
+
+const (
+	ClientClaudeDesktop Client = "claude-desktop"
+	ClientVSCode        Client = "vscode"
+	ClientCursor        Client = "cursor"
+)
+
+// Clients lists the supported clients
+var Clients = []Client{ClientClaudeDesktop, ClientVSCode, ClientCursor}
+
+var (
+	// ErrUnsupportedClient is returned for a client that isn't in Clients
+	ErrUnsupportedClient = errors.New("unsupported client")
+	// ErrNotInstallable is returned when a server has neither a package with a known runtime
+	// nor a remote endpoint
+	ErrNotInstallable = errors.New("server has no package or remote that can be configured")
+)
+
+// Config is a client configuration file fragment. Claude Desktop and Cursor key their servers
+// under "mcpServers"; VS Code uses "servers" and declares prompted values under "inputs".
+type Config struct {
+	Inputs     []Input                 `json:"inputs,omitempty"`
+	Servers    map[string]ServerConfig `json:"servers,omitempty"`
+	MCPServers map[string]ServerConfig `json:"mcpServers,omitempty"`
+	// Variables documents the values the user has to supply to Claude Desktop and Cursor, which
+	// can't declare them: the <NAME> placeholders to replace, or the environment variables that
+	// ${env:NAME} references read. It is not part of the client's file format.
+	Variables []Variable `json:"variables,omitempty"`
+}
+
+// ServerConfig launches a local server (Command) or connects to a remote one (URL)
+type ServerConfig struct {
+	Type    string            `json:"type,omitempty"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Input is a VS Code input variable, prompted for when the server starts
+type Input struct {
+	Type        string   `json:"type"`
+	ID          string   `json:"id"`
+	Description string   `json:"description,omitempty"`
+	Password    bool     `json:"password,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Options     []string `json:"options,omitempty"`
+}
+
+// Variable is a value that the user has to supply, named as it appears in the configuration
+type Variable struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Secret      bool     `json:"secret,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Choices     []string `json:"choices,omitempty"`
+}
+
+// Generate builds the configuration for client. The first package whose runtime is known is
+// used; otherwise the first remote. Values the user has to provide, including all secrets, are
+// rendered as variables in the client's own syntax rather than filled in.
+func Generate(server *model.ServerDetail, client Client) (*Config, error) {
+	g := &generator{client: client, declared: map[string]bool{}}
+	switch client {
+	case ClientClaudeDesktop, ClientCursor, ClientVSCode:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedClient, client)
+	}
+
+	var (
+		serverConfig ServerConfig
+		ok           bool
+	)
+	for _, pkg := range server.Packages {
+		if serverConfig, ok = g.packageConfig(pkg); ok {
+			break
+		}
+	}
+	if !ok && len(server.Remotes) > 0 {
+		serverConfig, ok = g.remoteConfig(server.Remotes[0]), true
+	}
+	if !ok {
+		return nil, ErrNotInstallable
+	}
+
+	servers := map[string]ServerConfig{serverKey(server.Name): serverConfig}
+	config := &Config{}
+	if client == ClientVSCode {
+		config.Servers = servers
+		config.Inputs = g.inputs
+	} else {
+		config.MCPServers = servers
+		config.Variables = g.variables
+	}
+	return config, nil
+}
+
+// serverKey derives the configuration key from the last segment of the server name
+func serverKey(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 && i < len(name)-1 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// generator accumulates the variables declared while rendering a server
+type generator struct {
+	client    Client
+	inputs    []Input
+	variables []Variable
+	declared  map[string]bool
+}
+
+func (g *generator) packageConfig(pkg model.Package) (ServerConfig, bool) {
+	// Environment variables are resolved first so their variables get the natural IDs
+	env := make(map[string]string, len(pkg.EnvironmentVariables))
+	for _, variable := range pkg.EnvironmentVariables {
+		env[variable.Name] = g.value(variable.InputWithVariables, variable.Name)
+	}
+
+	command, args, err := launch.Runner(pkg, sortedKeys(env))
+	if err != nil {
+		return ServerConfig{}, false
+	}
+	args = append(args, g.arguments(launch.RuntimeArguments, pkg.RuntimeArguments)...)
+	args = append(args, launch.PackageReference(pkg))
+	args = append(args, g.arguments(launch.PackageArguments, pkg.PackageArguments)...)
+
+	config := ServerConfig{Command: command, Args: args}
+	if g.client == ClientVSCode {
+		config.Type = "stdio"
+	}
+	if len(env) > 0 {
+		config.Env = env
+	}
+	return config, true
+}
+
+func (g *generator) arguments(list launch.ArgumentList, arguments []model.Argument) []string {
+	var args []string
+	for i, arg := range arguments {
+		id := launch.ArgumentKey(list, arg, i+1)
+
+		if arg.Type == model.ArgumentTypeNamed {
+			switch {
+			case arg.Format == model.FormatBoolean && arg.Value == "":
+				// A boolean flag is passed alone when it defaults to on and left out otherwise
+				if arg.Default == "true" {
+					args = append(args, arg.Name)
+				}
+				continue
+			case arg.Value == "" && arg.Default == "" && !arg.IsRequired:
+				// Optional flags without a value are left out, as they are when launching
+				continue
+			}
+			args = append(args, arg.Name)
+		}
+		args = append(args, g.value(arg.InputWithVariables, id))
+	}
+	return args
+}
+
+func (g *generator) remoteConfig(remote model.Remote) ServerConfig {
+	headers := make(map[string]string, len(remote.Headers))
+	for _, header := range remote.Headers {
+		// Headers published without a name can't be sent
+		if header.Name == "" {
+			continue
+		}
+		headers[header.Name] = g.value(header.InputWithVariables, header.Name)
+	}
+
+	// Claude Desktop only launches local servers, so remotes go through the mcp-remote bridge
+	if g.client == ClientClaudeDesktop {
+		args := []string{"-y", "mcp-remote", remote.URL}
+		for _, name := range sortedKeys(headers) {
+			args = append(args, "--header", name+":"+headers[name])
+		}
+		return ServerConfig{Command: "npx", Args: args}
+	}
+
+	config := ServerConfig{URL: remote.URL}
+	if len(headers) > 0 {
+		config.Headers = headers
+	}
+	if g.client == ClientVSCode {
+		config.Type = "http"
+		if remote.TransportType == "sse" {
+			config.Type = "sse"
+		}
+	}
+	return config
+}
+
+// value renders input as a literal when the registry fixes it and as a variable otherwise.
+// Variables referenced from the value as {name} are declared in their own right.
+func (g *generator) value(input model.InputWithVariables, id string) string {
+	if input.Value != "" {
+		return model.TemplateVariable.ReplaceAllStringFunc(input.Value, func(match string) string {
+			name := match[1 : len(match)-1]
+			variable, ok := input.Variables[name]
+			if !ok {
+				return match
+			}
+			return g.variable(name, variable)
+		})
+	}
+	if input.Default != "" && !input.IsSecret {
+		return input.Default
+	}
+	return g.variable(id, input.Input)
+}
+
+// variable declares a user-supplied value and returns a reference to it in the client's syntax.
+// Claude Desktop has no variables, so it gets a placeholder for the user to replace.
+func (g *generator) variable(id string, input model.Input) string {
+	if g.client != ClientVSCode {
+		g.declareVariable(envName(id), input)
+	}
+	switch g.client {
+	case ClientVSCode:
+		if !g.declared[id] {
+			g.declared[id] = true
+			declaration := Input{
+				Type:        "promptString",
+				ID:          id,
+				Description: input.Description,
+				Password:    input.IsSecret,
+			}
+			if !input.IsSecret {
+				declaration.Default = input.Default
+			}
+			if len(input.Choices) > 0 {
+				declaration.Type = "pickString"
+				declaration.Options = input.Choices
+				declaration.Password = false
+			}
+			g.inputs = append(g.inputs, declaration)
+		}
+		return "${input:" + id + "}"
+	case ClientCursor:
+		return "${env:" + envName(id) + "}"
+	default:
+		return "<" + envName(id) + ">"
+	}
+}
+
+// declareVariable records a value the user has to supply to a client without input declarations
+func (g *generator) declareVariable(name string, input model.Input) {
+	if g.declared[name] {
+		return
+	}
+	g.declared[name] = true
+	variable := Variable{
+		Name:        name,
+		Description: input.Description,
+		Secret:      input.IsSecret,
+		Choices:     input.Choices,
+	}
+	if !input.IsSecret {
+		variable.Default = input.Default
+	}
+	g.variables = append(g.variables, variable)
+}
+
+// envName turns an input ID into an environment variable style name
+func envName(id string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(id) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return strings.Trim(b.String(), "_")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
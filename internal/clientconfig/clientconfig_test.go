@@ -0,0 +1,179 @@
+package clientconfig_test
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/clientconfig"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	npmServer := &model.ServerDetail{
+		Server: model.Server{Name: "io.github.example/weather"},
+		Packages: []model.Package{{
+			RegistryName: "npm",
+			Name:         "@example/weather",
+			Version:      "1.2.0",
+			PackageArguments: []model.Argument{
+				{
+					Type: model.ArgumentTypeNamed,
+					Name: "--units",
+					InputWithVariables: model.InputWithVariables{Input: model.Input{
+						Default: "metric",
+					}},
+				},
+				{
+					// Unset optional flags are left out rather than passed without their value
+					Type: model.ArgumentTypeNamed,
+					Name: "--log-file",
+				},
+				{
+					Type:      model.ArgumentTypePositional,
+					ValueHint: "region",
+					InputWithVariables: model.InputWithVariables{Input: model.Input{
+						Description: "Forecast region",
+						IsRequired:  true,
+						Choices:     []string{"eu", "us"},
+					}},
+				},
+			},
+			EnvironmentVariables: []model.KeyValueInput{{
+				Name: "WEATHER_API_KEY",
+				InputWithVariables: model.InputWithVariables{Input: model.Input{
+					Description: "API key",
+					IsSecret:    true,
+				}},
+			}},
+		}},
+	}
+
+	t.Run("claude desktop", func(t *testing.T) {
+		config, err := clientconfig.Generate(npmServer, clientconfig.ClientClaudeDesktop)
+		require.NoError(t, err)
+		assert.Nil(t, config.Inputs)
+		assert.Equal(t, clientconfig.ServerConfig{
+			Command: "npx",
+			Args:    []string{"-y", "@example/weather@1.2.0", "--units", "metric", "<REGION>"},
+			Env:     map[string]string{"WEATHER_API_KEY": "<WEATHER_API_KEY>"},
+		}, config.MCPServers["weather"])
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		config, err := clientconfig.Generate(npmServer, clientconfig.ClientCursor)
+		require.NoError(t, err)
+		server := config.MCPServers["weather"]
+		assert.Equal(t, "${env:REGION}", server.Args[len(server.Args)-1])
+		assert.Equal(t, "${env:WEATHER_API_KEY}", server.Env["WEATHER_API_KEY"])
+		assert.Equal(t, []clientconfig.Variable{
+			{Name: "WEATHER_API_KEY", Description: "API key", Secret: true},
+			{Name: "REGION", Description: "Forecast region", Choices: []string{"eu", "us"}},
+		}, config.Variables)
+	})
+
+	t.Run("vscode", func(t *testing.T) {
+		config, err := clientconfig.Generate(npmServer, clientconfig.ClientVSCode)
+		require.NoError(t, err)
+		assert.Nil(t, config.MCPServers)
+		server := config.Servers["weather"]
+		assert.Equal(t, "stdio", server.Type)
+		assert.Equal(t, "${input:region}", server.Args[len(server.Args)-1])
+		assert.Equal(t, "${input:WEATHER_API_KEY}", server.Env["WEATHER_API_KEY"])
+		assert.Equal(t, []clientconfig.Input{
+			{Type: "promptString", ID: "WEATHER_API_KEY", Description: "API key", Password: true},
+			{Type: "pickString", ID: "region", Description: "Forecast region", Options: []string{"eu", "us"}},
+		}, config.Inputs)
+	})
+
+	t.Run("docker passes environment through", func(t *testing.T) {
+		server := &model.ServerDetail{
+			Server: model.Server{Name: "io.github.example/db"},
+			Packages: []model.Package{{
+				RegistryName: "docker",
+				Name:         "example/db",
+				Version:      "2",
+				EnvironmentVariables: []model.KeyValueInput{{
+					Name: "DB_URL",
+					InputWithVariables: model.InputWithVariables{
+						Input: model.Input{Value: "postgres://{host}/app"},
+						Variables: map[string]model.Input{
+							"host": {Description: "Database host", IsRequired: true},
+						},
+					},
+				}},
+			}},
+		}
+		config, err := clientconfig.Generate(server, clientconfig.ClientClaudeDesktop)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"run", "-i", "--rm", "-e", "DB_URL", "example/db:2"}, config.MCPServers["db"].Args)
+		assert.Equal(t, "postgres://<HOST>/app", config.MCPServers["db"].Env["DB_URL"])
+	})
+
+	t.Run("unnamed positional arguments get an input each", func(t *testing.T) {
+		server := &model.ServerDetail{
+			Server: model.Server{Name: "io.github.example/db"},
+			Packages: []model.Package{{
+				RegistryName: "docker",
+				Name:         "example/db",
+				Version:      "2",
+				RuntimeArguments: []model.Argument{{
+					Type:               model.ArgumentTypePositional,
+					InputWithVariables: model.InputWithVariables{Input: model.Input{IsRequired: true}},
+				}},
+				PackageArguments: []model.Argument{{
+					Type:               model.ArgumentTypePositional,
+					InputWithVariables: model.InputWithVariables{Input: model.Input{IsRequired: true}},
+				}},
+			}},
+		}
+		config, err := clientconfig.Generate(server, clientconfig.ClientVSCode)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"run", "-i", "--rm", "${input:runtime_arg_1}", "example/db:2", "${input:package_arg_1}"},
+			config.Servers["db"].Args)
+		assert.Len(t, config.Inputs, 2)
+	})
+
+	t.Run("remote", func(t *testing.T) {
+		server := &model.ServerDetail{
+			Server:   model.Server{Name: "io.github.example/remote"},
+			Packages: []model.Package{{RegistryName: "unknown", Name: "remote"}},
+			Remotes: []model.Remote{{
+				TransportType: "sse",
+				URL:           "https://example.com/sse",
+				Headers: []model.KeyValueInput{
+					{
+						Name: "Authorization",
+						InputWithVariables: model.InputWithVariables{Input: model.Input{
+							Description: "Bearer token",
+							IsSecret:    true,
+						}},
+					},
+					// Headers without a name can't be sent
+					{InputWithVariables: model.InputWithVariables{Input: model.Input{Value: "v1"}}},
+				},
+			}},
+		}
+
+		config, err := clientconfig.Generate(server, clientconfig.ClientVSCode)
+		require.NoError(t, err)
+		assert.Equal(t, clientconfig.ServerConfig{
+			Type:    "sse",
+			URL:     "https://example.com/sse",
+			Headers: map[string]string{"Authorization": "${input:Authorization}"},
+		}, config.Servers["remote"])
+
+		config, err = clientconfig.Generate(server, clientconfig.ClientClaudeDesktop)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"-y", "mcp-remote", "https://example.com/sse", "--header", "Authorization:<AUTHORIZATION>"},
+			config.MCPServers["remote"].Args)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := clientconfig.Generate(npmServer, "emacs")
+		require.ErrorIs(t, err, clientconfig.ErrUnsupportedClient)
+
+		_, err = clientconfig.Generate(&model.ServerDetail{}, clientconfig.ClientCursor)
+		require.ErrorIs(t, err, clientconfig.ErrNotInstallable)
+	})
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"net/netip"
+	"time"
+
 	env "github.com/caarlos0/env/v11"
 )
 
@@ -11,6 +14,28 @@ const (
 	DatabaseTypeMemory  DatabaseType = "memory"
 )
 
+type RateLimitStoreType string
+
+const (
+	RateLimitStoreMemory   RateLimitStoreType = "memory"
+	RateLimitStoreDatabase RateLimitStoreType = "database"
+)
+
+type TracingExporterType string
+
+const (
+	TracingExporterNone TracingExporterType = "none"
+	TracingExporterOTLP TracingExporterType = "otlp"
+)
+
+type OpenAPIValidationMode string
+
+const (
+	OpenAPIValidationOff     OpenAPIValidationMode = "off"
+	OpenAPIValidationReport  OpenAPIValidationMode = "report"
+	OpenAPIValidationEnforce OpenAPIValidationMode = "enforce"
+)
+
 // Config holds the application configuration
 type Config struct {
 	ServerAddress      string       `env:"SERVER_ADDRESS" envDefault:":8080"`
@@ -24,6 +49,61 @@ type Config struct {
 	Version            string       `env:"VERSION" envDefault:"dev"`
 	GithubClientID     string       `env:"GITHUB_CLIENT_ID" envDefault:""`
 	GithubClientSecret string       `env:"GITHUB_CLIENT_SECRET" envDefault:""`
+	GithubOAuthURL     string       `env:"GITHUB_OAUTH_URL" envDefault:"https://github.com"`
+	GithubAPIURL       string       `env:"GITHUB_API_URL" envDefault:"https://api.github.com"`
+
+	GithubTimeout              time.Duration `env:"GITHUB_TIMEOUT" envDefault:"10s"`
+	GithubMaxRetries           int           `env:"GITHUB_MAX_RETRIES" envDefault:"2"`
+	GithubAuthCacheTTL         time.Duration `env:"GITHUB_AUTH_CACHE_TTL" envDefault:"5m"`
+	GithubAuthNegativeCacheTTL time.Duration `env:"GITHUB_AUTH_NEGATIVE_CACHE_TTL" envDefault:"30s"`
+	GithubOIDCIssuer           string        `env:"GITHUB_OIDC_ISSUER" envDefault:"https://token.actions.githubusercontent.com"`
+	GithubOIDCJWKSURL          string        `env:"GITHUB_OIDC_JWKS_URL" envDefault:"https://token.actions.githubusercontent.com/.well-known/jwks"`
+	GithubOIDCAudience         string        `env:"GITHUB_OIDC_AUDIENCE" envDefault:"mcp-registry"`
+	GithubOIDCJWKSCacheTTL     time.Duration `env:"GITHUB_OIDC_JWKS_CACHE_TTL" envDefault:"1h"`
+	GitlabURL                  string        `env:"GITLAB_URL" envDefault:"https://gitlab.com"`
+	GitlabTimeout              time.Duration `env:"GITLAB_TIMEOUT" envDefault:"10s"`
+	AdminIdentities            []string      `env:"ADMIN_IDENTITIES" envDefault:"" envSeparator:","`
+
+	DomainVerificationCacheTTL         time.Duration `env:"DOMAIN_VERIFICATION_CACHE_TTL" envDefault:"1h"`
+	DomainVerificationNegativeCacheTTL time.Duration `env:"DOMAIN_VERIFICATION_NEGATIVE_CACHE_TTL" envDefault:"1m"`
+	WellKnownTimeout                   time.Duration `env:"WELL_KNOWN_TIMEOUT" envDefault:"5s"`
+
+	RateLimitStore           RateLimitStoreType `env:"RATE_LIMIT_STORE" envDefault:"memory"`
+	PublishRateLimitPerHour  int                `env:"PUBLISH_RATE_LIMIT_PER_HOUR" envDefault:"30"`
+	PublishVersionsPerDay    int                `env:"PUBLISH_VERSIONS_PER_DAY" envDefault:"100"`
+	AuthFlowRateLimitPerHour int                `env:"AUTH_FLOW_RATE_LIMIT_PER_HOUR" envDefault:"20"`
+
+	// TrustedProxies lists the networks, in CIDR notation, of proxies whose X-Forwarded-For
+	// header identifies the client
+	TrustedProxies []netip.Prefix `env:"TRUSTED_PROXIES" envDefault:"" envSeparator:","`
+
+	IdempotencyKeyTTL time.Duration `env:"IDEMPOTENCY_KEY_TTL" envDefault:"24h"`
+
+	CacheControlServerList   string `env:"CACHE_CONTROL_SERVER_LIST" envDefault:"public, max-age=30"`
+	CacheControlServerDetail string `env:"CACHE_CONTROL_SERVER_DETAIL" envDefault:"public, max-age=300"`
+
+	BatchGetMaxItems int `env:"BATCH_GET_MAX_ITEMS" envDefault:"100"`
+
+	// CursorSecret signs pagination cursors. If empty, a random secret is generated at startup.
+	CursorSecret string `env:"CURSOR_SECRET" envDefault:""`
+
+	AccessLog           bool  `env:"ACCESS_LOG" envDefault:"true"`
+	MaxRequestBodyBytes int64 `env:"MAX_REQUEST_BODY_BYTES" envDefault:"1048576"`
+
+	HealthCheckTimeout time.Duration `env:"HEALTH_CHECK_TIMEOUT" envDefault:"2s"`
+	HealthCacheTTL     time.Duration `env:"HEALTH_CACHE_TTL" envDefault:"5s"`
+	HealthCheckGitHub  bool          `env:"HEALTH_CHECK_GITHUB" envDefault:"false"`
+	ShutdownDelay      time.Duration `env:"SHUTDOWN_DELAY" envDefault:"0s"`
+
+	OpenAPIValidation OpenAPIValidationMode `env:"OPENAPI_VALIDATION" envDefault:"off"`
+
+	MetricsEnabled bool   `env:"METRICS_ENABLED" envDefault:"false"`
+	MetricsAddress string `env:"METRICS_ADDRESS" envDefault:""`
+
+	TracingExporter    TracingExporterType `env:"TRACING_EXPORTER" envDefault:"none"`
+	TracingSampleRatio float64             `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
+	OTLPEndpoint       string              `env:"OTLP_ENDPOINT" envDefault:""`
+	OTLPInsecure       bool                `env:"OTLP_INSECURE" envDefault:"false"`
 }
 
 // NewConfig creates a new configuration with default values
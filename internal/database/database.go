@@ -3,8 +3,10 @@ package database
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/projection"
 )
 
 // Common database errors
@@ -18,18 +20,159 @@ var (
 
 // Database defines the interface for database operations on MCPRegistry entries
 type Database interface {
-	// List retrieves all MCPRegistry entries with optional filtering
-	List(ctx context.Context, filter map[string]interface{}, cursor string, limit int) ([]*model.Server, string, error)
+	// ListWithOptions retrieves MCPRegistry entries with optional filtering in the order, page
+	// and shape given by opts. Only the latest version of each server is listed.
+	ListWithOptions(ctx context.Context, filter map[string]interface{}, opts ListOptions) (*ListResult, error)
 	// GetByID retrieves a single ServerDetail by it's ID
 	GetByID(ctx context.Context, id string) (*model.ServerDetail, error)
-	// Publish adds a new ServerDetail to the database
-	Publish(ctx context.Context, serverDetail *model.ServerDetail) error
-	// ImportSeed imports initial data from a seed file
-	ImportSeed(ctx context.Context, seedFilePath string) error
+	// GetByIDProjected retrieves a single ServerDetail by its ID with only the fields selected by
+	// p set. The ID and release date are always included.
+	GetByIDProjected(ctx context.Context, id string, p projection.Projection) (*model.ServerDetail, error)
+	// BatchGet retrieves, in a single query, the server details whose ID is in ids or whose
+	// name and version match one of refs. Requested entries that don't exist are left out.
+	BatchGet(ctx context.Context, ids []string, refs []ServerRef) ([]*model.ServerDetail, error)
+	// Publish adds a new ServerDetail to the database. The record built by auditFn is written to
+	// the audit log in the same transaction where the backend supports them, so that a publish
+	// is never applied unaudited.
+	Publish(ctx context.Context, serverDetail *model.ServerDetail, auditFn AuditFunc) error
+	// ImportSeed imports initial data from a seed file. The record built by auditFn for each
+	// created or updated entry is written to the audit log together with the entry, in the same
+	// transaction where the backend supports them, so that partial imports are audited too.
+	ImportSeed(ctx context.Context, seedFilePath string, auditFn SeedAuditFunc) (ImportStats, error)
+	// Export opens a consistent point-in-time snapshot of all server details, ordered by ID.
+	// The caller must close the returned iterator.
+	Export(ctx context.Context) (ServerIterator, error)
+	// InsertAuditRecord appends an immutable record to the audit log
+	InsertAuditRecord(ctx context.Context, record *model.AuditRecord) error
+	// ListAuditRecords retrieves audit records, newest first, with optional filtering and pagination
+	ListAuditRecords(ctx context.Context, filter AuditFilter, cursor string, limit int) ([]*model.AuditRecord, string, error)
+	// InsertIdempotencyRecord stores the response to an idempotent request. It returns
+	// ErrAlreadyExists if an unexpired record with the same key exists; expired ones are replaced.
+	InsertIdempotencyRecord(ctx context.Context, record *model.IdempotencyRecord) error
+	// GetIdempotencyRecord retrieves the unexpired record with the given key, or ErrNotFound
+	GetIdempotencyRecord(ctx context.Context, key string) (*model.IdempotencyRecord, error)
+	// UpdateIdempotencyRecord replaces the unexpired record with the same key, or returns
+	// ErrNotFound if there is none
+	UpdateIdempotencyRecord(ctx context.Context, record *model.IdempotencyRecord) error
+	// DeleteIdempotencyRecord deletes the record with the given key, if any
+	DeleteIdempotencyRecord(ctx context.Context, key string) error
+	// IncrementCounter increments a fixed-window counter for key and returns the new count
+	// and the time at which the window resets
+	IncrementCounter(ctx context.Context, key string, window time.Duration) (int, time.Time, error)
+	// DecrementCounter undoes an IncrementCounter for key that returned resetAt. Counters of
+	// windows that have ended, and counters that are already zero, are left alone.
+	DecrementCounter(ctx context.Context, key string, window time.Duration, resetAt time.Time) error
 	// Close closes the database connection
 	Close() error
 }
 
+// AuditFunc builds the audit record of a publish. It is called once the new entry has its ID,
+// with the previous latest version of the server as it was before the publish, or nil.
+type AuditFunc func(previous *model.ServerDetail) *model.AuditRecord
+
+// SeedAuditFunc builds the audit record of a seed entry that is created or updated, with the
+// stored entry as it was before the import, or nil for a new entry, and the imported entry
+type SeedAuditFunc func(previous, imported *model.ServerDetail) *model.AuditRecord
+
+// AuditFilter selects audit records. Empty fields match every record.
+type AuditFilter struct {
+	Actor     string
+	Operation model.AuditOperation
+	Target    string
+	TargetID  string
+	// Since and Until bound the timestamps of the records to [Since, Until)
+	Since time.Time
+	Until time.Time
+}
+
+// SortOrder orders the results of a list query. Entries with equal sort keys are ordered by ID.
+type SortOrder string
+
+const (
+	// SortByID orders by ID, which is the default
+	SortByID SortOrder = ""
+	// SortByName orders by server name
+	SortByName SortOrder = "name"
+	// SortByReleaseDate orders by release date, oldest first
+	SortByReleaseDate SortOrder = "release_date"
+	// SortByReleaseDateDesc orders by release date, newest first
+	SortByReleaseDateDesc SortOrder = "-release_date"
+)
+
+// SortOrders lists the supported orders other than the default
+var SortOrders = []SortOrder{SortByName, SortByReleaseDate, SortByReleaseDateDesc}
+
+// Position is where a page of a list query ends: the sort key and ID of its last entry. Keyset
+// positions stay stable when entries are added or removed between pages.
+type Position struct {
+	Key string
+	ID  string
+}
+
+// ListOptions controls the order, pagination and shape of a list query
+type ListOptions struct {
+	// After is the Next position of the previous page, which must have used the same Sort
+	After *Position
+	Limit int
+	Sort  SortOrder
+	// IncludeTotal counts the entries matching the filter across all pages
+	IncludeTotal bool
+	// Fields selects the fields to set on the results; empty selects all. The ID, name and
+	// release date are always included.
+	Fields projection.Projection
+}
+
+// ListResult is a page of a list query
+type ListResult struct {
+	Servers []*model.ServerDetail
+	// Next is the position of the last entry if more entries follow, or nil
+	Next *Position
+	// Total is the number of entries matching the filter, if ListOptions.IncludeTotal was set
+	Total int
+}
+
+// ServerRef identifies a server version by name and version
+type ServerRef struct {
+	Name    string
+	Version string
+}
+
+// String formats the reference as name@version
+func (r ServerRef) String() string {
+	return r.Name + "@" + r.Version
+}
+
+// ImportStats summarizes the outcome of a seed import
+type ImportStats struct {
+	// Total is the number of servers in the seed file
+	Total int
+	// Created is the number of servers that did not exist before the import
+	Created int
+	// Updated is the number of existing servers that were changed by the import
+	Updated int
+	// Unchanged is the number of existing servers that were already up to date
+	Unchanged int
+	// Skipped is the number of servers ignored because they lack an ID or name
+	Skipped int
+	// Failed is the number of servers that could not be written
+	Failed int
+}
+
+// ServerIterator streams the server details of an export snapshot
+type ServerIterator interface {
+	// ChangeSeq returns the change sequence watermark of the snapshot: every change with a
+	// sequence number up to and including it is reflected in the snapshot
+	ChangeSeq() int64
+	// Next advances to the next server detail and reports whether there is one
+	Next(ctx context.Context) bool
+	// Server returns the current server detail
+	Server() *model.ServerDetail
+	// Err returns the error, if any, that stopped the iteration
+	Err() error
+	// Close releases the snapshot
+	Close(ctx context.Context) error
+}
+
 // ConnectionType represents the type of database connection
 type ConnectionType string
 
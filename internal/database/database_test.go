@@ -0,0 +1,146 @@
+package database_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/audit"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBackends returns each database backend to run a test against: the in-memory database,
+// and MongoDB if MCP_REGISTRY_TEST_DATABASE_URL is set
+func testBackends(t *testing.T) map[string]func(t *testing.T) database.Database {
+	t.Helper()
+	backends := map[string]func(t *testing.T) database.Database{
+		"memory": func(*testing.T) database.Database {
+			return database.NewMemoryDB(map[string]*model.Server{})
+		},
+	}
+	if os.Getenv("MCP_REGISTRY_TEST_DATABASE_URL") != "" {
+		backends["mongo"] = func(t *testing.T) database.Database {
+			return newTestMongoDB(t)
+		}
+	}
+	return backends
+}
+
+// seedImportRecord audits seed imports as the system actor
+func seedImportRecord(previous, imported *model.ServerDetail) *model.AuditRecord {
+	ctx := audit.WithActor(context.Background(), audit.SystemActor)
+	return audit.SeedImportRecord(ctx, previous, imported)
+}
+
+// importTestSeed imports servers into db from a seed file
+func importTestSeed(t *testing.T, db database.Database, servers []model.ServerDetail) {
+	t.Helper()
+	content, err := json.Marshal(servers)
+	require.NoError(t, err)
+	seedPath := filepath.Join(t.TempDir(), "seed.json")
+	require.NoError(t, os.WriteFile(seedPath, content, 0o600))
+	stats, err := db.ImportSeed(context.Background(), seedPath, seedImportRecord)
+	require.NoError(t, err)
+	require.Equal(t, len(servers), stats.Created)
+}
+
+func TestListWithOptionsListsLatestVersions(t *testing.T) {
+	seed := []model.ServerDetail{
+		{Server: model.Server{
+			ID: "a-1", Name: "io.github.example/a",
+			VersionDetail: model.VersionDetail{Version: "1.0.0", ReleaseDate: "2025-05-16T00:00:00Z"},
+		}},
+		{Server: model.Server{
+			ID: "a-2", Name: "io.github.example/a",
+			VersionDetail: model.VersionDetail{Version: "1.1.0", ReleaseDate: "2025-05-20T00:00:00Z", IsLatest: true},
+		}},
+		{Server: model.Server{
+			ID: "b-1", Name: "io.github.example/b",
+			VersionDetail: model.VersionDetail{Version: "0.1.0", ReleaseDate: "2025-05-18T00:00:00Z", IsLatest: true},
+		}},
+	}
+
+	for name, newDB := range testBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			db := newDB(t)
+			importTestSeed(t, db, seed)
+			ctx := context.Background()
+
+			result, err := db.ListWithOptions(ctx, nil, database.ListOptions{Limit: 1, IncludeTotal: true})
+			require.NoError(t, err)
+			assert.Equal(t, 2, result.Total)
+			require.Len(t, result.Servers, 1)
+			require.NotNil(t, result.Next)
+
+			next, err := db.ListWithOptions(ctx, nil, database.ListOptions{After: result.Next, Limit: 10})
+			require.NoError(t, err)
+			require.Len(t, next.Servers, 1)
+			assert.Nil(t, next.Next)
+
+			ids := []string{result.Servers[0].ID, next.Servers[0].ID}
+			assert.ElementsMatch(t, []string{"a-2", "b-1"}, ids)
+
+			filtered, err := db.ListWithOptions(ctx, map[string]interface{}{"name": "io.github.example/a"},
+				database.ListOptions{Limit: 10, IncludeTotal: true})
+			require.NoError(t, err)
+			assert.Equal(t, 1, filtered.Total)
+			require.Len(t, filtered.Servers, 1)
+			assert.Equal(t, "a-2", filtered.Servers[0].ID)
+		})
+	}
+}
+
+func TestListWithOptionsSortsByReleaseTime(t *testing.T) {
+	// As strings, these release dates would sort c, b, a
+	seed := []model.ServerDetail{
+		{Server: model.Server{
+			ID: "a", Name: "io.github.example/a",
+			VersionDetail: model.VersionDetail{Version: "1.0.0", ReleaseDate: "2025-05-16T10:00:00+05:00", IsLatest: true},
+		}},
+		{Server: model.Server{
+			ID: "b", Name: "io.github.example/b",
+			VersionDetail: model.VersionDetail{Version: "1.0.0", ReleaseDate: "2025-05-16T06:00:00Z", IsLatest: true},
+		}},
+		{Server: model.Server{
+			ID: "c", Name: "io.github.example/c",
+			VersionDetail: model.VersionDetail{Version: "1.0.0", ReleaseDate: "2025-05-16T01:30:00-02:30", IsLatest: true},
+		}},
+	}
+
+	for name, newDB := range testBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			db := newDB(t)
+			importTestSeed(t, db, seed)
+
+			for _, sort := range []database.SortOrder{database.SortByReleaseDate, database.SortByReleaseDateDesc} {
+				// Page through one entry at a time to check that positions agree with the order
+				var ids, dates []string
+				opts := database.ListOptions{Limit: 1, Sort: sort}
+				for {
+					result, err := db.ListWithOptions(context.Background(), nil, opts)
+					require.NoError(t, err)
+					for _, server := range result.Servers {
+						ids = append(ids, server.ID)
+						dates = append(dates, server.VersionDetail.ReleaseDate)
+					}
+					if result.Next == nil {
+						break
+					}
+					opts.After = result.Next
+				}
+
+				if sort == database.SortByReleaseDate {
+					assert.Equal(t, []string{"c", "a", "b"}, ids)
+					assert.Equal(t, []string{"2025-05-16T04:00:00Z", "2025-05-16T05:00:00Z", "2025-05-16T06:00:00Z"}, dates)
+				} else {
+					assert.Equal(t, []string{"b", "a", "c"}, ids)
+				}
+			}
+		})
+	}
+}
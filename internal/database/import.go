@@ -39,6 +39,11 @@ func ReadSeedFile(path string) ([]model.ServerDetail, error) {
 		}
 	}
 
+	// Store release dates in the same format as published ones, so that they sort alike
+	for i := range servers {
+		servers[i].VersionDetail.ReleaseDate = normalizeReleaseDate(servers[i].VersionDetail.ReleaseDate)
+	}
+
 	log.Printf("Found %d server entries in seed file", len(servers))
 	return servers, nil
 }
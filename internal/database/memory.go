@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
@@ -12,25 +13,45 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/projection"
 )
 
 // MemoryDB is an in-memory implementation of the Database interface
 type MemoryDB struct {
-	entries map[string]*model.ServerDetail
-	mu      sync.RWMutex
+	entries     map[string]*model.ServerDetail
+	audit       []*model.AuditRecord
+	counters    map[string]*memoryCounter
+	idempotency map[string]*model.IdempotencyRecord
+	changeSeq   int64
+	// countersExpireAt is the earliest reset time of the stored counters, before which there
+	// are no expired counters to drop
+	countersExpireAt time.Time
+	mu               sync.RWMutex
+}
+
+// memoryCounter is a fixed-window counter stored by IncrementCounter
+type memoryCounter struct {
+	count   int
+	resetAt time.Time
 }
 
 // NewMemoryDB creates a new instance of the in-memory database
 func NewMemoryDB(e map[string]*model.Server) *MemoryDB {
 	// Convert Server entries to ServerDetail entries
 	serverDetails := make(map[string]*model.ServerDetail)
+	var changeSeq int64
 	for k, v := range e {
+		changeSeq++
 		serverDetails[k] = &model.ServerDetail{
-			Server: *v,
+			Server:    *v,
+			ChangeSeq: changeSeq,
 		}
 	}
 	return &MemoryDB{
-		entries: serverDetails,
+		entries:     serverDetails,
+		counters:    make(map[string]*memoryCounter),
+		idempotency: make(map[string]*model.IdempotencyRecord),
+		changeSeq:   changeSeq,
 	}
 }
 
@@ -85,19 +106,17 @@ func compareSemanticVersions(version1, version2 string) int {
 	return 0
 }
 
-// List retrieves all MCPRegistry entries with optional filtering and pagination
-//
-//gocognit:ignore
-func (db *MemoryDB) List(
+// ListWithOptions retrieves entries in the order, page and shape given by opts
+func (db *MemoryDB) ListWithOptions(
 	ctx context.Context,
 	filter map[string]interface{},
-	cursor string,
-	limit int,
-) ([]*model.Server, string, error) {
+	opts ListOptions,
+) (*ListResult, error) {
 	if ctx.Err() != nil {
-		return nil, "", ctx.Err()
+		return nil, ctx.Err()
 	}
 
+	limit := opts.Limit
 	if limit <= 0 {
 		limit = 10 // Default limit
 	}
@@ -105,82 +124,76 @@ func (db *MemoryDB) List(
 	db.mu.RLock()
 	defer db.mu.RUnlock()
 
-	// Convert all entries to a slice for pagination
-	var allEntries []*model.Server
+	// Like MongoDB, only list the latest version of each server
+	var filteredEntries []*model.ServerDetail
 	for _, entry := range db.entries {
-		serverCopy := entry.Server
-		allEntries = append(allEntries, &serverCopy)
-	}
-
-	// Simple filtering implementation
-	var filteredEntries []*model.Server
-	for _, entry := range allEntries {
-		include := true
-
-		// Apply filters if any
-		for key, value := range filter {
-			switch key {
-			case "name":
-				if entry.Name != value.(string) {
-					include = false
-				}
-			case "repoUrl":
-				if entry.Repository.URL != value.(string) {
-					include = false
-				}
-			case "serverDetail.id":
-				if entry.ID != value.(string) {
-					include = false
-				}
-			case "version":
-				if entry.VersionDetail.Version != value.(string) {
-					include = false
-				}
-				// Add more filter options as needed
-			}
-		}
-
-		if include {
+		if entry.VersionDetail.IsLatest && matchesServerFilter(entry, filter) {
 			filteredEntries = append(filteredEntries, entry)
 		}
 	}
 
-	// Find starting point for cursor-based pagination
-	startIdx := 0
-	if cursor != "" {
-		for i, entry := range filteredEntries {
-			if entry.ID == cursor {
-				startIdx = i + 1 // Start after the cursor
-				break
-			}
-		}
-	}
-
-	// Sort filteredEntries by ID for consistent pagination
+	// Sort filteredEntries for consistent pagination
 	sort.Slice(filteredEntries, func(i, j int) bool {
-		return filteredEntries[i].ID < filteredEntries[j].ID
+		a, b := filteredEntries[i], filteredEntries[j]
+		return compareKeys(sortKey(a, opts.Sort), a.ID, sortKey(b, opts.Sort), b.ID, opts.Sort) < 0
 	})
 
+	// Start after the given position, which needn't be an entry that still exists
+	startIdx := 0
+	if after := opts.After; after != nil {
+		startIdx = sort.Search(len(filteredEntries), func(i int) bool {
+			entry := filteredEntries[i]
+			return compareKeys(sortKey(entry, opts.Sort), entry.ID, after.Key, after.ID, opts.Sort) > 0
+		})
+	}
+
 	// Apply pagination
 	endIdx := startIdx + limit
 	if endIdx > len(filteredEntries) {
 		endIdx = len(filteredEntries)
 	}
 
-	var result []*model.Server
-	if startIdx < len(filteredEntries) {
-		result = filteredEntries[startIdx:endIdx]
-	} else {
-		result = []*model.Server{}
+	result := &ListResult{Servers: []*model.ServerDetail{}}
+	fields := opts.Fields.With(projection.Required...)
+	for _, entry := range filteredEntries[startIdx:endIdx] {
+		result.Servers = append(result.Servers, fields.Mask(entry))
 	}
 
 	// Determine next cursor
-	nextCursor := ""
 	if endIdx < len(filteredEntries) {
-		nextCursor = filteredEntries[endIdx-1].ID
+		result.Next = positionOf(filteredEntries[endIdx-1], opts.Sort)
+	}
+	if opts.IncludeTotal {
+		result.Total = len(filteredEntries)
 	}
 
-	return result, nextCursor, nil
+	return result, nil
+}
+
+// matchesServerFilter reports whether an entry satisfies all filter conditions
+func matchesServerFilter(entry *model.ServerDetail, filter map[string]interface{}) bool {
+	for key, value := range filter {
+		switch key {
+		case "name":
+			if entry.Name != value.(string) {
+				return false
+			}
+		case "repoUrl":
+			if entry.Repository.URL != value.(string) {
+				return false
+			}
+		case "serverDetail.id":
+			if entry.ID != value.(string) {
+				return false
+			}
+		case "version":
+			if entry.VersionDetail.Version != value.(string) {
+				return false
+			}
+			// Add more filter options as needed
+		}
+	}
+	return true
 }
 
 // GetByID retrieves a single ServerDetail by its ID
@@ -201,8 +214,60 @@ func (db *MemoryDB) GetByID(ctx context.Context, id string) (*model.ServerDetail
 	return nil, ErrNotFound
 }
 
-// Publish adds a new ServerDetail to the database
-func (db *MemoryDB) Publish(ctx context.Context, serverDetail *model.ServerDetail) error {
+// GetByIDProjected retrieves a single ServerDetail by its ID, with only the fields selected by p set
+func (db *MemoryDB) GetByIDProjected(ctx context.Context, id string, p projection.Projection) (*model.ServerDetail, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if entry, exists := db.entries[id]; exists {
+		return p.With(projection.Required...).Mask(entry), nil
+	}
+
+	return nil, ErrNotFound
+}
+
+// BatchGet retrieves the server details with the given IDs or names and versions
+func (db *MemoryDB) BatchGet(ctx context.Context, ids []string, refs []ServerRef) ([]*model.ServerDetail, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	found := make(map[string]*model.ServerDetail, len(ids)+len(refs))
+	for _, id := range ids {
+		if entry, exists := db.entries[id]; exists {
+			found[id] = entry
+		}
+	}
+	// Entries aren't indexed by name, so match references in a single pass
+	if len(refs) > 0 {
+		wanted := make(map[ServerRef]bool, len(refs))
+		for _, ref := range refs {
+			wanted[ref] = true
+		}
+		for id, entry := range db.entries {
+			if wanted[ServerRef{Name: entry.Name, Version: entry.VersionDetail.Version}] {
+				found[id] = entry
+			}
+		}
+	}
+
+	result := make([]*model.ServerDetail, 0, len(found))
+	for _, entry := range found {
+		serverDetailCopy := *entry
+		result = append(result, &serverDetailCopy)
+	}
+	return result, nil
+}
+
+// Publish adds a new ServerDetail to the database, along with its audit record
+func (db *MemoryDB) Publish(ctx context.Context, serverDetail *model.ServerDetail, auditFn AuditFunc) error {
 	if ctx.Err() != nil {
 		return ctx.Err()
 	}
@@ -218,6 +283,7 @@ func (db *MemoryDB) Publish(ctx context.Context, serverDetail *model.ServerDetai
 	// check that the name and the version are unique
 	// Also check version ordering - don't allow publishing older versions after newer ones
 	var latestVersion string
+	var previous *model.ServerDetail
 	for _, entry := range db.entries {
 		if entry.Name == serverDetail.Name {
 			if entry.VersionDetail.Version == serverDetail.VersionDetail.Version {
@@ -228,6 +294,9 @@ func (db *MemoryDB) Publish(ctx context.Context, serverDetail *model.ServerDetai
 			if latestVersion == "" || compareSemanticVersions(entry.VersionDetail.Version, latestVersion) > 0 {
 				latestVersion = entry.VersionDetail.Version
 			}
+			if entry.VersionDetail.IsLatest {
+				previous = entry
+			}
 		}
 	}
 
@@ -243,25 +312,48 @@ func (db *MemoryDB) Publish(ctx context.Context, serverDetail *model.ServerDetai
 	// Generate a new ID for the server detail
 	serverDetail.ID = uuid.New().String()
 	serverDetail.VersionDetail.IsLatest = true // Assume the new version is the latest
-	serverDetail.VersionDetail.ReleaseDate = time.Now().Format(time.RFC3339)
+	serverDetail.VersionDetail.ReleaseDate = formatReleaseDate(time.Now())
+	serverDetail.ChangeSeq = db.changeSeq + 1
+
+	// Build the audit record before changing anything, so that a publish without a valid
+	// record leaves no trace
+	record := auditFn(previous)
+	if record == nil || record.ID == "" || record.Operation == "" {
+		return ErrInvalidInput
+	}
+	recordCopy := *record
+	db.audit = append(db.audit, &recordCopy)
+
+	db.changeSeq++
 	// Store a copy of the entire ServerDetail
 	serverDetailCopy := *serverDetail
 	db.entries[serverDetail.ID] = &serverDetailCopy
 
+	// Replace the previous latest version rather than modifying it, so that open exports
+	// keep seeing it as it was
+	if previous != nil {
+		previousCopy := *previous
+		previousCopy.VersionDetail.IsLatest = false
+		previousCopy.ChangeSeq = db.changeSeq
+		db.entries[previous.ID] = &previousCopy
+	}
+
 	return nil
 }
 
 // ImportSeed imports initial data from a seed file into memory database
-func (db *MemoryDB) ImportSeed(ctx context.Context, seedFilePath string) error {
+func (db *MemoryDB) ImportSeed(ctx context.Context, seedFilePath string, auditFn SeedAuditFunc) (ImportStats, error) {
+	var stats ImportStats
 	if ctx.Err() != nil {
-		return ctx.Err()
+		return stats, ctx.Err()
 	}
 
 	// Read the seed file
 	seedData, err := ReadSeedFile(seedFilePath)
 	if err != nil {
-		return fmt.Errorf("failed to read seed file: %w", err)
+		return stats, fmt.Errorf("failed to read seed file: %w", err)
 	}
+	stats.Total = len(seedData)
 
 	log.Printf("Importing %d servers into memory database", len(seedData))
 
@@ -271,27 +363,348 @@ func (db *MemoryDB) ImportSeed(ctx context.Context, seedFilePath string) error {
 	for i, server := range seedData {
 		if server.ID == "" || server.Name == "" {
 			log.Printf("Skipping server %d: ID or Name is empty", i+1)
+			stats.Skipped++
 			continue
 		}
 
 		// Set default version information if missing
 		if server.VersionDetail.Version == "" {
 			server.VersionDetail.Version = "0.0.1-seed"
-			server.VersionDetail.ReleaseDate = time.Now().Format(time.RFC3339)
+			server.VersionDetail.ReleaseDate = formatReleaseDate(time.Now())
 			server.VersionDetail.IsLatest = true
 		}
 
 		// Store a copy of the server detail
 		serverDetailCopy := server
-		db.entries[server.ID] = &serverDetailCopy
+		existing, exists := db.entries[server.ID]
+		if exists && equalIgnoringChangeSeq(existing, &serverDetailCopy) {
+			// Like MongoDB, leave the change sequence of unchanged entries alone so that
+			// re-importing the seed doesn't show up as a change in exports
+			log.Printf("[%d/%d] Server already up to date: %s", i+1, len(seedData), server.Name)
+			stats.Unchanged++
+			continue
+		}
+
+		// Like Publish, build the audit record before changing anything
+		record := auditFn(existing, &serverDetailCopy)
+		if record == nil || record.ID == "" || record.Operation == "" {
+			log.Printf("Error importing server %s: %v", server.ID, ErrInvalidInput)
+			stats.Failed++
+			continue
+		}
+		recordCopy := *record
+		db.audit = append(db.audit, &recordCopy)
+
+		if exists {
+			log.Printf("[%d/%d] Updated server: %s", i+1, len(seedData), server.Name)
+			stats.Updated++
+		} else {
+			log.Printf("[%d/%d] Created server: %s", i+1, len(seedData), server.Name)
+			stats.Created++
+		}
 
-		log.Printf("[%d/%d] Imported server: %s", i+1, len(seedData), server.Name)
+		db.changeSeq++
+		serverDetailCopy.ChangeSeq = db.changeSeq
+		db.entries[server.ID] = &serverDetailCopy
 	}
 
 	log.Println("Memory database import completed successfully")
+	return stats, nil
+}
+
+// equalIgnoringChangeSeq reports whether two server details are the same apart from their change
+// sequence numbers
+func equalIgnoringChangeSeq(a, b *model.ServerDetail) bool {
+	aCopy, bCopy := *a, *b
+	aCopy.ChangeSeq, bCopy.ChangeSeq = 0, 0
+	return reflect.DeepEqual(aCopy, bCopy)
+}
+
+// Export returns a snapshot of all server details, ordered by ID
+func (db *MemoryDB) Export(ctx context.Context) (ServerIterator, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	// Entries are replaced rather than modified in place, so copying the pointers is enough
+	// to keep the snapshot stable while writes continue
+	servers := make([]*model.ServerDetail, 0, len(db.entries))
+	for _, entry := range db.entries {
+		servers = append(servers, entry)
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].ID < servers[j].ID
+	})
+
+	return &memoryIterator{servers: servers, changeSeq: db.changeSeq, pos: -1}, nil
+}
+
+// memoryIterator iterates over a copied slice of server details
+type memoryIterator struct {
+	servers   []*model.ServerDetail
+	changeSeq int64
+	pos       int
+	err       error
+}
+
+func (it *memoryIterator) ChangeSeq() int64 {
+	return it.changeSeq
+}
+
+func (it *memoryIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+	it.pos++
+	return it.pos < len(it.servers)
+}
+
+func (it *memoryIterator) Server() *model.ServerDetail {
+	serverDetailCopy := *it.servers[it.pos]
+	return &serverDetailCopy
+}
+
+func (it *memoryIterator) Err() error {
+	return it.err
+}
+
+func (it *memoryIterator) Close(_ context.Context) error {
+	it.servers = nil
 	return nil
 }
 
+// InsertAuditRecord appends an immutable record to the in-memory audit log
+func (db *MemoryDB) InsertAuditRecord(ctx context.Context, record *model.AuditRecord) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if record == nil || record.ID == "" || record.Operation == "" {
+		return ErrInvalidInput
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	// Store a copy so later changes by the caller can't alter the log
+	recordCopy := *record
+	db.audit = append(db.audit, &recordCopy)
+
+	return nil
+}
+
+// ListAuditRecords retrieves audit records, newest first, with optional filtering and pagination
+func (db *MemoryDB) ListAuditRecords(
+	ctx context.Context,
+	filter AuditFilter,
+	cursor string,
+	limit int,
+) ([]*model.AuditRecord, string, error) {
+	if ctx.Err() != nil {
+		return nil, "", ctx.Err()
+	}
+
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	// Record IDs are time-ordered, so sorting by ID descending yields newest first
+	var filtered []*model.AuditRecord
+	for _, record := range db.audit {
+		if cursor != "" && record.ID >= cursor {
+			continue
+		}
+		if matchesAuditFilter(record, filter) {
+			recordCopy := *record
+			filtered = append(filtered, &recordCopy)
+		}
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].ID > filtered[j].ID
+	})
+
+	if len(filtered) <= limit {
+		return filtered, "", nil
+	}
+
+	result := filtered[:limit]
+	return result, result[len(result)-1].ID, nil
+}
+
+// matchesAuditFilter reports whether an audit record satisfies all filter conditions
+func matchesAuditFilter(record *model.AuditRecord, filter AuditFilter) bool {
+	switch {
+	case filter.Actor != "" && record.Actor != filter.Actor,
+		filter.Operation != "" && record.Operation != filter.Operation,
+		filter.Target != "" && record.Target != filter.Target,
+		filter.TargetID != "" && record.TargetID != filter.TargetID,
+		!filter.Since.IsZero() && record.Timestamp.Before(filter.Since),
+		!filter.Until.IsZero() && !record.Timestamp.Before(filter.Until):
+		return false
+	}
+	return true
+}
+
+// InsertIdempotencyRecord stores the response to an idempotent request
+func (db *MemoryDB) InsertIdempotencyRecord(ctx context.Context, record *model.IdempotencyRecord) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if record == nil || record.Key == "" {
+		return ErrInvalidInput
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	// Drop expired records so the map doesn't grow without bound
+	now := time.Now()
+	for k, r := range db.idempotency {
+		if !now.Before(r.ExpiresAt) {
+			delete(db.idempotency, k)
+		}
+	}
+
+	if _, exists := db.idempotency[record.Key]; exists {
+		return ErrAlreadyExists
+	}
+	recordCopy := *record
+	db.idempotency[record.Key] = &recordCopy
+
+	return nil
+}
+
+// GetIdempotencyRecord retrieves the unexpired record with the given key
+func (db *MemoryDB) GetIdempotencyRecord(ctx context.Context, key string) (*model.IdempotencyRecord, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	record, exists := db.idempotency[key]
+	if !exists || !time.Now().Before(record.ExpiresAt) {
+		return nil, ErrNotFound
+	}
+	recordCopy := *record
+	return &recordCopy, nil
+}
+
+// UpdateIdempotencyRecord replaces the unexpired record with the same key
+func (db *MemoryDB) UpdateIdempotencyRecord(ctx context.Context, record *model.IdempotencyRecord) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if record == nil || record.Key == "" {
+		return ErrInvalidInput
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	existing, exists := db.idempotency[record.Key]
+	if !exists || !time.Now().Before(existing.ExpiresAt) {
+		return ErrNotFound
+	}
+	recordCopy := *record
+	db.idempotency[record.Key] = &recordCopy
+
+	return nil
+}
+
+// DeleteIdempotencyRecord deletes the record with the given key
+func (db *MemoryDB) DeleteIdempotencyRecord(ctx context.Context, key string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	delete(db.idempotency, key)
+	return nil
+}
+
+// IncrementCounter increments a fixed-window counter for key
+func (db *MemoryDB) IncrementCounter(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	if ctx.Err() != nil {
+		return 0, time.Time{}, ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	now := time.Now()
+	counterKey, windowStart := windowKey(key, now, window)
+
+	counter, exists := db.counters[counterKey]
+	if !exists {
+		db.dropExpiredCounters(now)
+		counter = &memoryCounter{resetAt: windowStart.Add(window)}
+		db.counters[counterKey] = counter
+		if db.countersExpireAt.IsZero() || counter.resetAt.Before(db.countersExpireAt) {
+			db.countersExpireAt = counter.resetAt
+		}
+	}
+	counter.count++
+
+	return counter.count, counter.resetAt, nil
+}
+
+// DecrementCounter decrements the fixed-window counter for key that resets at resetAt
+func (db *MemoryDB) DecrementCounter(ctx context.Context, key string, window time.Duration, resetAt time.Time) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	counterKey, _ := windowKey(key, resetAt.Add(-window), window)
+	if counter, exists := db.counters[counterKey]; exists && counter.count > 0 {
+		counter.count--
+	}
+	return nil
+}
+
+// dropExpiredCounters removes the counters of past windows, once any of them has expired
+func (db *MemoryDB) dropExpiredCounters(now time.Time) {
+	if db.countersExpireAt.IsZero() || now.Before(db.countersExpireAt) {
+		return
+	}
+
+	db.countersExpireAt = time.Time{}
+	for k, c := range db.counters {
+		switch {
+		case !now.Before(c.resetAt):
+			delete(db.counters, k)
+		case db.countersExpireAt.IsZero() || c.resetAt.Before(db.countersExpireAt):
+			db.countersExpireAt = c.resetAt
+		}
+	}
+}
+
+// windowKey returns the key of the counter for key in the window of the given length containing
+// now, and the start of that window
+func windowKey(key string, now time.Time, window time.Duration) (string, time.Time) {
+	windowStart := now.Truncate(window)
+	return key + ":" + strconv.FormatInt(windowStart.Unix(), 10), windowStart
+}
+
 // Close closes the database connection
 // For an in-memory database, this is a no-op
 func (db *MemoryDB) Close() error {
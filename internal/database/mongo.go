@@ -9,22 +9,40 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/projection"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const (
+	// auditCollectionName is the name of the collection holding the audit log
+	auditCollectionName = "audit_log"
+	// countersCollectionName is the name of the collection holding fixed-window counters
+	countersCollectionName = "counters"
+	// idempotencyCollectionName is the name of the collection holding idempotent request results
+	idempotencyCollectionName = "idempotency_keys"
+	// changeSeqCounterID is the ID of the counters document holding the server change sequence
+	changeSeqCounterID = "change_seq:servers"
+)
+
 // MongoDB is an implementation of the Database interface using MongoDB
 type MongoDB struct {
-	client     *mongo.Client
-	database   *mongo.Database
-	collection *mongo.Collection
+	client                *mongo.Client
+	database              *mongo.Database
+	collection            *mongo.Collection
+	auditCollection       *mongo.Collection
+	countersCollection    *mongo.Collection
+	idempotencyCollection *mongo.Collection
+	// transactions is whether the server supports multi-document transactions, which only
+	// replica sets and sharded clusters do
+	transactions bool
 }
 
 // NewMongoDB creates a new instance of the MongoDB database
 func NewMongoDB(ctx context.Context, connectionURI, databaseName, collectionName string) (*MongoDB, error) {
 	// Set client options and connect to MongoDB
-	clientOptions := options.Client().ApplyURI(connectionURI)
+	clientOptions := options.Client().ApplyURI(connectionURI).SetMonitor(newCommandMonitor())
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, err
@@ -37,6 +55,19 @@ func NewMongoDB(ctx context.Context, connectionURI, databaseName, collectionName
 
 	// Get database and collection
 	database := client.Database(databaseName)
+
+	// Standalone servers have no replica set name and aren't a mongos router
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err = database.RunCommand(ctx, bson.D{bson.E{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return nil, fmt.Errorf("error checking server topology: %w", err)
+	}
+	transactions := hello.SetName != "" || hello.Msg == "isdbgrid"
+	if !transactions {
+		log.Printf("MongoDB is a standalone server, writes will not use transactions")
+	}
 	collection := database.Collection(collectionName)
 
 	// Create indexes for better query performance
@@ -53,6 +84,14 @@ func NewMongoDB(ctx context.Context, connectionURI, databaseName, collectionName
 			Keys:    bson.D{bson.E{Key: "name", Value: 1}, bson.E{Key: "versiondetail.version", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
+		// sorted listings page by release date, with ties ordered by ID
+		{
+			Keys: bson.D{bson.E{Key: "version_detail.release_date", Value: 1}, bson.E{Key: "id", Value: 1}},
+		},
+		// exports look up the highest change sequence in their snapshot
+		{
+			Keys: bson.D{bson.E{Key: "change_seq", Value: -1}},
+		},
 	}
 
 	_, err = collection.Indexes().CreateMany(ctx, models)
@@ -65,27 +104,89 @@ func NewMongoDB(ctx context.Context, connectionURI, databaseName, collectionName
 		log.Printf("Indexes already exists, skipping.")
 	}
 
+	auditCollection := database.Collection(auditCollectionName)
+	auditModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{bson.E{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{bson.E{Key: "actor", Value: 1}, bson.E{Key: "id", Value: -1}},
+		},
+		{
+			Keys: bson.D{bson.E{Key: "target", Value: 1}, bson.E{Key: "id", Value: -1}},
+		},
+	}
+
+	_, err = auditCollection.Indexes().CreateMany(ctx, auditModels)
+	if err != nil {
+		var commandError mongo.CommandError
+		if errors.As(err, &commandError) && commandError.Code != 86 {
+			return nil, err
+		}
+		log.Printf("Audit indexes already exists, skipping.")
+	}
+
+	// Counters expire automatically once their window has passed
+	countersCollection := database.Collection(countersCollectionName)
+	_, err = countersCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{bson.E{Key: "reset_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		var commandError mongo.CommandError
+		if errors.As(err, &commandError) && commandError.Code != 86 {
+			return nil, err
+		}
+		log.Printf("Counter indexes already exists, skipping.")
+	}
+
+	// Idempotency records expire automatically too; the unique key index makes concurrent
+	// inserts of the same key fail
+	idempotencyCollection := database.Collection(idempotencyCollectionName)
+	_, err = idempotencyCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{bson.E{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{bson.E{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		var commandError mongo.CommandError
+		if errors.As(err, &commandError) && commandError.Code != 86 {
+			return nil, err
+		}
+		log.Printf("Idempotency indexes already exists, skipping.")
+	}
+
 	return &MongoDB{
-		client:     client,
-		database:   database,
-		collection: collection,
+		client:                client,
+		database:              database,
+		collection:            collection,
+		auditCollection:       auditCollection,
+		countersCollection:    countersCollection,
+		idempotencyCollection: idempotencyCollection,
+		transactions:          transactions,
 	}, nil
 }
 
-// List retrieves MCPRegistry entries with optional filtering and pagination
-func (db *MongoDB) List(
+// ListWithOptions retrieves entries in the order, page and shape given by opts
+func (db *MongoDB) ListWithOptions(
 	ctx context.Context,
 	filter map[string]interface{},
-	cursor string,
-	limit int,
-) ([]*model.Server, string, error) {
+	opts ListOptions,
+) (*ListResult, error) {
+	limit := opts.Limit
 	if limit <= 0 {
 		// Set default limit if not provided
 		limit = 10
 	}
 
 	if ctx.Err() != nil {
-		return nil, "", ctx.Err()
+		return nil, ctx.Err()
 	}
 
 	// Convert Go map to MongoDB filter
@@ -105,73 +206,114 @@ func (db *MongoDB) List(
 		}
 	}
 
-	// Setup pagination options
-	findOptions := options.Find()
-
-	// If cursor is provided, add condition to filter to only get records after the cursor
-	if cursor != "" {
-		// Validate that the cursor is a valid UUID
-		if _, err := uuid.Parse(cursor); err != nil {
-			return nil, "", fmt.Errorf("invalid cursor format: %w", err)
+	result := &ListResult{}
+	if opts.IncludeTotal {
+		total, err := db.collection.CountDocuments(ctx, mongoFilter)
+		if err != nil {
+			return nil, err
 		}
+		result.Total = int(total)
+	}
 
-		// Fetch the document at the cursor to get its sort values
-		var cursorDoc model.Server
-		err := db.collection.FindOne(ctx, bson.M{"id": cursor}).Decode(&cursorDoc)
-		if err != nil {
-			if !errors.Is(err, mongo.ErrNoDocuments) {
-				return nil, "", err
-			}
-			// If cursor document not found, start from beginning
+	sortField, direction := mongoSort(opts.Sort)
+
+	// If a position is provided, add condition to filter to only get records after it. The
+	// entry at the position needn't exist anymore.
+	if after := opts.After; after != nil {
+		if opts.Sort == SortByID {
+			mongoFilter["id"] = bson.M{"$gt": after.ID}
 		} else {
-			// Use the cursor document's ID to paginate (records with ID > cursor's ID)
-			mongoFilter["id"] = bson.M{"$gt": cursor}
+			op := "$gt"
+			if direction < 0 {
+				op = "$lt"
+			}
+			mongoFilter = bson.M{"$and": bson.A{mongoFilter, bson.M{"$or": bson.A{
+				bson.M{sortField: bson.M{op: after.Key}},
+				bson.M{sortField: after.Key, "id": bson.M{"$gt": after.ID}},
+			}}}}
 		}
 	}
 
-	// Set sort order by ID (for consistent pagination)
-	findOptions.SetSort(bson.M{"id": 1})
-
-	// Set limit if provided and valid
-	if limit > 0 {
-		findOptions.SetLimit(int64(limit))
+	// Setup pagination options; ties are ordered by ID for consistent pagination
+	findOptions := options.Find()
+	if opts.Sort == SortByID {
+		findOptions.SetSort(bson.D{{Key: "id", Value: 1}})
+	} else {
+		findOptions.SetSort(bson.D{{Key: sortField, Value: direction}, {Key: "id", Value: 1}})
+	}
+	findOptions.SetLimit(int64(limit))
+	if fields := mongoProjection(opts.Fields); fields != nil {
+		findOptions.SetProjection(fields)
 	}
 
 	// Execute find operation with options
 	mongoCursor, err := db.collection.Find(ctx, mongoFilter, findOptions)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 	defer mongoCursor.Close(ctx)
 
 	// Decode results
-	var results []*model.Server
-	if err = mongoCursor.All(ctx, &results); err != nil {
-		return nil, "", err
+	result.Servers = []*model.ServerDetail{}
+	if err = mongoCursor.All(ctx, &result.Servers); err != nil {
+		return nil, err
 	}
 
 	// Determine the next cursor
-	nextCursor := ""
-	if len(results) > 0 && limit > 0 && len(results) >= limit {
-		// Use the last item's ID as the next cursor
-		nextCursor = results[len(results)-1].ID
+	if len(result.Servers) > 0 && len(result.Servers) >= limit {
+		result.Next = positionOf(result.Servers[len(result.Servers)-1], opts.Sort)
 	}
 
-	return results, nextCursor, nil
+	return result, nil
+}
+
+// mongoSort returns the document path and direction of a sort order
+func mongoSort(sort SortOrder) (string, int) {
+	switch sort {
+	case SortByName:
+		return "name", 1
+	case SortByReleaseDate:
+		return "version_detail.release_date", 1
+	case SortByReleaseDateDesc:
+		return "version_detail.release_date", -1
+	default:
+		return "id", 1
+	}
+}
+
+// mongoProjection converts p into a projection document, or nil to fetch whole documents
+func mongoProjection(p projection.Projection) bson.M {
+	if len(p) == 0 {
+		return nil
+	}
+	doc := bson.M{"_id": 0}
+	for _, path := range p.With(projection.Required...).BSONPaths() {
+		doc[path] = 1
+	}
+	return doc
 }
 
 // GetByID retrieves a single ServerDetail by its ID
 func (db *MongoDB) GetByID(ctx context.Context, id string) (*model.ServerDetail, error) {
+	return db.GetByIDProjected(ctx, id, nil)
+}
+
+// GetByIDProjected retrieves a single ServerDetail by its ID, fetching only the fields selected by p
+func (db *MongoDB) GetByIDProjected(ctx context.Context, id string, p projection.Projection) (*model.ServerDetail, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
 	// Create a filter for the ID
 	filter := bson.M{"id": id}
+	opts := options.FindOne()
+	if fields := mongoProjection(p); fields != nil {
+		opts.SetProjection(fields)
+	}
 
 	// Find the entry in the database
 	var entry model.ServerDetail
-	err := db.collection.FindOne(ctx, filter).Decode(&entry)
+	err := db.collection.FindOne(ctx, filter, opts).Decode(&entry)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, ErrNotFound
@@ -183,11 +325,71 @@ func (db *MongoDB) GetByID(ctx context.Context, id string) (*model.ServerDetail,
 	return &entry, nil
 }
 
-// Publish adds a new ServerDetail to the database
-func (db *MongoDB) Publish(ctx context.Context, serverDetail *model.ServerDetail) error {
+// BatchGet retrieves the server details with the given IDs or names and versions in one query
+func (db *MongoDB) BatchGet(ctx context.Context, ids []string, refs []ServerRef) ([]*model.ServerDetail, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	clauses := bson.A{}
+	if len(ids) > 0 {
+		clauses = append(clauses, bson.M{"id": bson.M{"$in": ids}})
+	}
+	for _, ref := range refs {
+		clauses = append(clauses, bson.M{"name": ref.Name, "version_detail.version": ref.Version})
+	}
+	if len(clauses) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := db.collection.Find(ctx, bson.M{"$or": clauses})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*model.ServerDetail
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("error decoding entries: %w", err)
+	}
+	return entries, nil
+}
+
+// Publish adds a new ServerDetail to the database. On a replica set the entry, the update of
+// the previous latest version and the audit record are written in one transaction.
+func (db *MongoDB) Publish(ctx context.Context, serverDetail *model.ServerDetail, auditFn AuditFunc) error {
 	if ctx.Err() != nil {
 		return ctx.Err()
 	}
+
+	_, err := db.withTransaction(ctx, func(ctx context.Context) (interface{}, error) {
+		return nil, db.publish(ctx, serverDetail, auditFn)
+	})
+	return err
+}
+
+// withTransaction runs fn in a transaction if the server supports them. On a standalone server
+// fn runs without one, so each of its writes is atomic but together they are not.
+func (db *MongoDB) withTransaction(
+	ctx context.Context, fn func(ctx context.Context) (interface{}, error),
+) (interface{}, error) {
+	if !db.transactions {
+		return fn(ctx)
+	}
+
+	session, err := db.client.StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("error starting session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	return session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return fn(sessCtx)
+	})
+}
+
+// publish performs the writes of Publish within the transaction carried by ctx, if any
+func (db *MongoDB) publish(ctx context.Context, serverDetail *model.ServerDetail, auditFn AuditFunc) error {
 	// find a server detail with the same name and check that the current version is greater than the existing one
 	filter := bson.M{
 		"name":                     serverDetail.Name,
@@ -201,13 +403,26 @@ func (db *MongoDB) Publish(ctx context.Context, serverDetail *model.ServerDetail
 	}
 
 	// check that the current version is greater than the existing one
-	if serverDetail.VersionDetail.Version <= existingEntry.VersionDetail.Version {
-		return fmt.Errorf("version must be greater than existing version")
+	var previous *model.ServerDetail
+	if existingEntry.ID != "" {
+		switch compareSemanticVersions(serverDetail.VersionDetail.Version, existingEntry.VersionDetail.Version) {
+		case 0:
+			return ErrAlreadyExists
+		case -1:
+			return ErrInvalidVersion
+		}
+		previous = &existingEntry
+	}
+
+	changeSeq, err := db.nextChangeSeq(ctx)
+	if err != nil {
+		return err
 	}
 
 	serverDetail.ID = uuid.New().String()
 	serverDetail.VersionDetail.IsLatest = true
-	serverDetail.VersionDetail.ReleaseDate = time.Now().Format(time.RFC3339)
+	serverDetail.VersionDetail.ReleaseDate = formatReleaseDate(time.Now())
+	serverDetail.ChangeSeq = changeSeq
 
 	// Insert the entry into the database
 	_, err = db.collection.InsertOne(ctx, serverDetail)
@@ -219,68 +434,430 @@ func (db *MongoDB) Publish(ctx context.Context, serverDetail *model.ServerDetail
 	}
 
 	// update the existing entry to not be the latest version
-	if existingEntry.ID != "" {
+	if previous != nil {
 		_, err = db.collection.UpdateOne(
 			ctx,
-			bson.M{"id": existingEntry.ID},
-			bson.M{"$set": bson.M{"versiondetail.islatest": false}})
+			bson.M{"id": previous.ID},
+			bson.M{"$set": bson.M{"version_detail.is_latest": false, "change_seq": changeSeq}})
 		if err != nil {
 			return fmt.Errorf("error updating existing entry: %w", err)
 		}
 	}
 
-	return nil
+	return db.InsertAuditRecord(ctx, auditFn(previous))
 }
 
 // ImportSeed imports initial data from a seed file into MongoDB
-func (db *MongoDB) ImportSeed(ctx context.Context, seedFilePath string) error {
+func (db *MongoDB) ImportSeed(ctx context.Context, seedFilePath string, auditFn SeedAuditFunc) (ImportStats, error) {
+	var stats ImportStats
+
 	// Read the seed file
 	servers, err := ReadSeedFile(seedFilePath)
 	if err != nil {
-		return fmt.Errorf("failed to read seed file: %w", err)
+		return stats, fmt.Errorf("failed to read seed file: %w", err)
 	}
+	stats.Total = len(servers)
 
-	collection := db.collection
-
-	log.Printf("Importing %d servers into collection %s", len(servers), collection.Name())
+	log.Printf("Importing %d servers into collection %s", len(servers), db.collection.Name())
 
 	for i, server := range servers {
 		if server.ID == "" || server.Name == "" {
 			log.Printf("Skipping server %d: ID or Name is empty", i+1)
+			stats.Skipped++
 			continue
 		}
 
 		if server.VersionDetail.Version == "" {
 			server.VersionDetail.Version = "0.0.1-seed"
-			server.VersionDetail.ReleaseDate = time.Now().Format(time.RFC3339)
+			server.VersionDetail.ReleaseDate = formatReleaseDate(time.Now())
 			server.VersionDetail.IsLatest = true
 		}
 
-		// Create filter based on server ID
-		filter := bson.M{"id": server.ID}
-
-		// Create update document
-		update := bson.M{"$set": server}
-
-		// Use upsert to create if not exists or update if exists
-		opts := options.Update().SetUpsert(true)
-		result, err := collection.UpdateOne(ctx, filter, update, opts)
+		result, err := db.withTransaction(ctx, func(ctx context.Context) (interface{}, error) {
+			return db.importServer(ctx, &server, auditFn)
+		})
 		if err != nil {
 			log.Printf("Error importing server %s: %v", server.ID, err)
+			stats.Failed++
 			continue
 		}
 
-		switch {
-		case result.UpsertedCount > 0:
+		switch updated := result.(*mongo.UpdateResult); {
+		case updated.UpsertedCount > 0:
 			log.Printf("[%d/%d] Created server: %s", i+1, len(servers), server.Name)
-		case result.ModifiedCount > 0:
+			stats.Created++
+		case updated.ModifiedCount > 0:
 			log.Printf("[%d/%d] Updated server: %s", i+1, len(servers), server.Name)
+			stats.Updated++
 		default:
 			log.Printf("[%d/%d] Server already up to date: %s", i+1, len(servers), server.Name)
+			stats.Unchanged++
 		}
 	}
 
 	log.Println("MongoDB database import completed successfully")
+	return stats, nil
+}
+
+// importServer upserts a seed server within the transaction carried by ctx, if any. Only entries that
+// actually changed get a new change sequence number and an audit record, so that re-importing an
+// unchanged seed doesn't show up as a change in exports or the audit log.
+func (db *MongoDB) importServer(
+	ctx context.Context, server *model.ServerDetail, auditFn SeedAuditFunc,
+) (*mongo.UpdateResult, error) {
+	var existingEntry model.ServerDetail
+	err := db.collection.FindOne(ctx, bson.M{"id": server.ID}).Decode(&existingEntry)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("error checking existing entry: %w", err)
+	}
+	var previous *model.ServerDetail
+	if existingEntry.ID != "" {
+		previous = &existingEntry
+	}
+
+	// Use upsert to create if not exists or update if exists
+	opts := options.Update().SetUpsert(true)
+	result, err := db.collection.UpdateOne(ctx, bson.M{"id": server.ID}, bson.M{"$set": server}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if result.UpsertedCount == 0 && result.ModifiedCount == 0 {
+		return result, nil
+	}
+
+	changeSeq, err := db.nextChangeSeq(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.collection.UpdateOne(ctx, bson.M{"id": server.ID}, bson.M{"$set": bson.M{"change_seq": changeSeq}})
+	if err != nil {
+		return nil, fmt.Errorf("error updating change sequence: %w", err)
+	}
+	if err := db.InsertAuditRecord(ctx, auditFn(previous, server)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// nextChangeSeq allocates the next server change sequence number. It must be called within the
+// transaction that writes the change: concurrent transactions conflict on the counter document,
+// so sequence numbers commit in order and together with the changes they number. On a
+// standalone server, which has no transactions, a change may become visible after changes with
+// higher numbers.
+func (db *MongoDB) nextChangeSeq(ctx context.Context) (int64, error) {
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := db.countersCollection.FindOneAndUpdate(
+		ctx, bson.M{"_id": changeSeqCounterID}, bson.M{"$inc": bson.M{"seq": 1}}, opts,
+	).Decode(&counter)
+	if err != nil {
+		return 0, fmt.Errorf("error allocating change sequence: %w", err)
+	}
+
+	return counter.Seq, nil
+}
+
+// Export streams all server details from a snapshot read, so that publishes during a long
+// export don't produce a mix of old and new state. Snapshot reads need a replica set; on a
+// standalone server the export fails rather than returning a snapshot that isn't one.
+func (db *MongoDB) Export(ctx context.Context) (ServerIterator, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	session, err := db.client.StartSession(options.Session().SetSnapshot(true))
+	if err != nil {
+		return nil, fmt.Errorf("error starting snapshot session: %w", err)
+	}
+
+	it, err := db.openExport(mongo.NewSessionContext(ctx, session))
+	if err != nil {
+		session.EndSession(ctx)
+		return nil, fmt.Errorf("snapshot read failed (exports need a replica set): %w", err)
+	}
+
+	it.session = session
+	return it, nil
+}
+
+// openExport reads the change sequence watermark and opens a cursor over all server details,
+// both within the snapshot session carried by ctx
+func (db *MongoDB) openExport(ctx context.Context) (*mongoIterator, error) {
+	// The watermark is the committed value of the change sequence counter. Sequence numbers are
+	// allocated in the transaction of the change they number, so every change up to it is in
+	// the snapshot.
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := db.countersCollection.FindOne(ctx, bson.M{"_id": changeSeqCounterID}).Decode(&counter)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("error reading change sequence: %w", err)
+	}
+
+	cursor, err := db.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("error opening export cursor: %w", err)
+	}
+
+	return &mongoIterator{cursor: cursor, changeSeq: counter.Seq}, nil
+}
+
+// mongoIterator iterates over a MongoDB cursor, decoding one server detail at a time
+type mongoIterator struct {
+	cursor    *mongo.Cursor
+	session   mongo.Session
+	changeSeq int64
+	current   *model.ServerDetail
+	err       error
+}
+
+func (it *mongoIterator) ChangeSeq() int64 {
+	return it.changeSeq
+}
+
+func (it *mongoIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if it.session != nil {
+		ctx = mongo.NewSessionContext(ctx, it.session)
+	}
+	if !it.cursor.Next(ctx) {
+		it.err = it.cursor.Err()
+		return false
+	}
+
+	var entry model.ServerDetail
+	if err := it.cursor.Decode(&entry); err != nil {
+		it.err = fmt.Errorf("error decoding entry: %w", err)
+		return false
+	}
+	it.current = &entry
+	return true
+}
+
+func (it *mongoIterator) Server() *model.ServerDetail {
+	return it.current
+}
+
+func (it *mongoIterator) Err() error {
+	return it.err
+}
+
+func (it *mongoIterator) Close(ctx context.Context) error {
+	err := it.cursor.Close(ctx)
+	if it.session != nil {
+		it.session.EndSession(ctx)
+	}
+	return err
+}
+
+// InsertAuditRecord appends an immutable record to the audit log collection
+func (db *MongoDB) InsertAuditRecord(ctx context.Context, record *model.AuditRecord) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if record == nil || record.ID == "" || record.Operation == "" {
+		return ErrInvalidInput
+	}
+
+	_, err := db.auditCollection.InsertOne(ctx, record)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("error inserting audit record: %w", err)
+	}
+
+	return nil
+}
+
+// ListAuditRecords retrieves audit records, newest first, with optional filtering and pagination
+func (db *MongoDB) ListAuditRecords(
+	ctx context.Context,
+	filter AuditFilter,
+	cursor string,
+	limit int,
+) ([]*model.AuditRecord, string, error) {
+	if ctx.Err() != nil {
+		return nil, "", ctx.Err()
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	mongoFilter := bson.M{}
+	for key, value := range map[string]string{
+		"actor":     filter.Actor,
+		"operation": string(filter.Operation),
+		"target":    filter.Target,
+		"target_id": filter.TargetID,
+	} {
+		if value != "" {
+			mongoFilter[key] = value
+		}
+	}
+	timestampFilter := bson.M{}
+	if !filter.Since.IsZero() {
+		timestampFilter["$gte"] = filter.Since
+	}
+	if !filter.Until.IsZero() {
+		timestampFilter["$lt"] = filter.Until
+	}
+	if len(timestampFilter) > 0 {
+		mongoFilter["timestamp"] = timestampFilter
+	}
+
+	// Record IDs are time-ordered, so paginate backwards from the cursor
+	if cursor != "" {
+		mongoFilter["id"] = bson.M{"$lt": cursor}
+	}
+
+	// Fetch one extra record to know whether there is a next page
+	findOptions := options.Find().
+		SetSort(bson.M{"id": -1}).
+		SetLimit(int64(limit) + 1)
+
+	mongoCursor, err := db.auditCollection.Find(ctx, mongoFilter, findOptions)
+	if err != nil {
+		return nil, "", err
+	}
+	defer mongoCursor.Close(ctx)
+
+	var results []*model.AuditRecord
+	if err = mongoCursor.All(ctx, &results); err != nil {
+		return nil, "", err
+	}
+
+	if len(results) <= limit {
+		return results, "", nil
+	}
+
+	results = results[:limit]
+	return results, results[len(results)-1].ID, nil
+}
+
+// InsertIdempotencyRecord stores the response to an idempotent request
+func (db *MongoDB) InsertIdempotencyRecord(ctx context.Context, record *model.IdempotencyRecord) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if record == nil || record.Key == "" {
+		return ErrInvalidInput
+	}
+
+	// The TTL monitor removes expired records only periodically, so replace an expired record
+	// in place. If an unexpired one exists the filter doesn't match it, and the upsert fails on
+	// the unique key index instead.
+	filter := bson.M{"key": record.Key, "expires_at": bson.M{"$lte": time.Now()}}
+	_, err := db.idempotencyCollection.ReplaceOne(ctx, filter, record, options.Replace().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("error inserting idempotency record: %w", err)
+	}
+
+	return nil
+}
+
+// GetIdempotencyRecord retrieves the unexpired record with the given key
+func (db *MongoDB) GetIdempotencyRecord(ctx context.Context, key string) (*model.IdempotencyRecord, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var record model.IdempotencyRecord
+	err := db.idempotencyCollection.FindOne(ctx, bson.M{"key": key, "expires_at": bson.M{"$gt": time.Now()}}).Decode(&record)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("error retrieving idempotency record: %w", err)
+	}
+
+	return &record, nil
+}
+
+// UpdateIdempotencyRecord replaces the unexpired record with the same key
+func (db *MongoDB) UpdateIdempotencyRecord(ctx context.Context, record *model.IdempotencyRecord) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if record == nil || record.Key == "" {
+		return ErrInvalidInput
+	}
+
+	filter := bson.M{"key": record.Key, "expires_at": bson.M{"$gt": time.Now()}}
+	result, err := db.idempotencyCollection.ReplaceOne(ctx, filter, record)
+	if err != nil {
+		return fmt.Errorf("error updating idempotency record: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyRecord deletes the record with the given key
+func (db *MongoDB) DeleteIdempotencyRecord(ctx context.Context, key string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if _, err := db.idempotencyCollection.DeleteOne(ctx, bson.M{"key": key}); err != nil {
+		return fmt.Errorf("error deleting idempotency record: %w", err)
+	}
+
+	return nil
+}
+
+// IncrementCounter atomically increments a fixed-window counter for key
+func (db *MongoDB) IncrementCounter(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	if ctx.Err() != nil {
+		return 0, time.Time{}, ctx.Err()
+	}
+
+	counterKey, windowStart := windowKey(key, time.Now(), window)
+	resetAt := windowStart.Add(window)
+
+	filter := bson.M{"_id": counterKey}
+	update := bson.M{
+		"$inc":         bson.M{"count": 1},
+		"$setOnInsert": bson.M{"reset_at": resetAt},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var counter struct {
+		Count int `bson:"count"`
+	}
+	if err := db.countersCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter); err != nil {
+		return 0, time.Time{}, fmt.Errorf("error incrementing counter: %w", err)
+	}
+
+	return counter.Count, resetAt, nil
+}
+
+// DecrementCounter atomically decrements the fixed-window counter for key that resets at resetAt
+func (db *MongoDB) DecrementCounter(ctx context.Context, key string, window time.Duration, resetAt time.Time) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	counterKey, _ := windowKey(key, resetAt.Add(-window), window)
+	filter := bson.M{"_id": counterKey, "count": bson.M{"$gt": 0}}
+	if _, err := db.countersCollection.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"count": -1}}); err != nil {
+		return fmt.Errorf("error decrementing counter: %w", err)
+	}
+
 	return nil
 }
 
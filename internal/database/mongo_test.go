@@ -0,0 +1,98 @@
+package database_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// newTestMongoDB connects to the server at MCP_REGISTRY_TEST_DATABASE_URL, which may be a
+// standalone server or a replica set, using a database that is dropped after the test
+func newTestMongoDB(t *testing.T) *database.MongoDB {
+	t.Helper()
+	uri := os.Getenv("MCP_REGISTRY_TEST_DATABASE_URL")
+	if uri == "" {
+		t.Skip("MCP_REGISTRY_TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	name := "mcp-registry-test-" + uuid.NewString()
+	db, err := database.NewMongoDB(ctx, uri, name, "servers")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+		require.NoError(t, err)
+		defer func() { _ = client.Disconnect(ctx) }()
+		assert.NoError(t, client.Database(name).Drop(ctx))
+		assert.NoError(t, db.Close())
+	})
+	return db
+}
+
+func TestMongoDBImportSeedAndPublish(t *testing.T) {
+	db := newTestMongoDB(t)
+	ctx := context.Background()
+
+	seed := []model.ServerDetail{
+		{Server: model.Server{
+			ID: "a", Name: "io.github.example/a",
+			VersionDetail: model.VersionDetail{Version: "1.0.0", ReleaseDate: "2025-05-16T00:00:00Z", IsLatest: true},
+		}},
+		{Server: model.Server{
+			ID: "b", Name: "io.github.example/b",
+			VersionDetail: model.VersionDetail{Version: "1.0.0", ReleaseDate: "2025-05-16T00:00:00Z", IsLatest: true},
+		}},
+	}
+	content, err := json.Marshal(seed)
+	require.NoError(t, err)
+	seedPath := filepath.Join(t.TempDir(), "seed.json")
+	require.NoError(t, os.WriteFile(seedPath, content, 0o600))
+
+	stats, err := db.ImportSeed(ctx, seedPath, seedImportRecord)
+	require.NoError(t, err)
+	assert.Equal(t, database.ImportStats{Total: 2, Created: 2}, stats)
+
+	// Re-importing the same seed changes nothing, and so audits nothing
+	stats, err = db.ImportSeed(ctx, seedPath, seedImportRecord)
+	require.NoError(t, err)
+	assert.Equal(t, database.ImportStats{Total: 2, Unchanged: 2}, stats)
+
+	seedRecords, _, err := db.ListAuditRecords(ctx, database.AuditFilter{Operation: model.AuditOperationSeedImport}, "", 10)
+	require.NoError(t, err)
+	require.Len(t, seedRecords, 2)
+	assert.Empty(t, seedRecords[0].BeforeDigest)
+	assert.Equal(t, "system", seedRecords[0].Actor)
+
+	next := &model.ServerDetail{Server: model.Server{
+		Name: "io.github.example/a", VersionDetail: model.VersionDetail{Version: "1.1.0"},
+	}}
+	err = db.Publish(ctx, next, func(previous *model.ServerDetail) *model.AuditRecord {
+		require.NotNil(t, previous)
+		assert.Equal(t, "a", previous.ID)
+		return &model.AuditRecord{
+			ID: uuid.NewString(), Operation: model.AuditOperationPublish, Target: next.Name, TargetID: next.ID,
+		}
+	})
+	require.NoError(t, err)
+
+	previous, err := db.GetByID(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, previous.VersionDetail.IsLatest)
+	published, err := db.GetByID(ctx, next.ID)
+	require.NoError(t, err)
+	assert.True(t, published.VersionDetail.IsLatest)
+
+	records, _, err := db.ListAuditRecords(ctx, database.AuditFilter{TargetID: next.ID}, "", 10)
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// commandTracer creates a client span for every command sent to MongoDB, so that each
+// FindOne, InsertOne, UpdateOne, ... issued by an operation shows up separately in its trace
+type commandTracer struct {
+	tracer trace.Tracer
+	// spans holds the in-flight spans keyed by the driver's request ID
+	spans sync.Map
+}
+
+// newCommandMonitor returns a command monitor that traces MongoDB commands
+func newCommandMonitor() *event.CommandMonitor {
+	t := &commandTracer{
+		tracer: otel.Tracer("github.com/modelcontextprotocol/registry/internal/database"),
+	}
+	return &event.CommandMonitor{
+		Started:   t.started,
+		Succeeded: t.succeeded,
+		Failed:    t.failed,
+	}
+}
+
+func (t *commandTracer) started(ctx context.Context, evt *event.CommandStartedEvent) {
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemMongoDB,
+		semconv.DBNamespace(evt.DatabaseName),
+		semconv.DBOperationName(evt.CommandName),
+	}
+	// For CRUD commands the first element of the command document names the collection
+	if collection, ok := evt.Command.Lookup(evt.CommandName).StringValueOK(); ok {
+		attrs = append(attrs, semconv.DBCollectionName(collection))
+	}
+
+	_, span := t.tracer.Start(ctx, "mongodb."+evt.CommandName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	t.spans.Store(evt.RequestID, span)
+}
+
+func (t *commandTracer) succeeded(_ context.Context, evt *event.CommandSucceededEvent) {
+	if span, ok := t.spans.LoadAndDelete(evt.RequestID); ok {
+		span.(trace.Span).End()
+	}
+}
+
+func (t *commandTracer) failed(_ context.Context, evt *event.CommandFailedEvent) {
+	if v, ok := t.spans.LoadAndDelete(evt.RequestID); ok {
+		span := v.(trace.Span)
+		span.RecordError(errors.New(evt.Failure))
+		span.SetStatus(codes.Error, evt.Failure)
+		span.End()
+	}
+}
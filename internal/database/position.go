@@ -0,0 +1,53 @@
+package database
+
+import (
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/model"
+)
+
+// positionOf returns the position of entry in a listing in the given order
+func positionOf(entry *model.ServerDetail, sort SortOrder) *Position {
+	return &Position{Key: sortKey(entry, sort), ID: entry.ID}
+}
+
+// sortKey returns the value entry is ordered by
+func sortKey(entry *model.ServerDetail, sort SortOrder) string {
+	switch sort {
+	case SortByName:
+		return entry.Name
+	case SortByReleaseDate, SortByReleaseDateDesc:
+		return normalizeReleaseDate(entry.VersionDetail.ReleaseDate)
+	default:
+		return ""
+	}
+}
+
+// compareKeys orders two entries, given by sort key and ID, for a listing in the given order
+func compareKeys(aKey, aID, bKey, bID string, sort SortOrder) int {
+	if c := strings.Compare(aKey, bKey); c != 0 {
+		if sort == SortByReleaseDateDesc {
+			return -c
+		}
+		return c
+	}
+	return strings.Compare(aID, bID)
+}
+
+// formatReleaseDate formats t the way release dates are stored: as an RFC 3339 timestamp in UTC
+// to the second, so that release dates order chronologically when compared as strings, as
+// MongoDB does when sorting
+func formatReleaseDate(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// normalizeReleaseDate converts an RFC 3339 release date to the stored format. Dates that don't
+// parse are returned unchanged.
+func normalizeReleaseDate(date string) string {
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return date
+	}
+	return formatReleaseDate(t)
+}
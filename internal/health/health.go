@@ -0,0 +1,232 @@
+// Package health reports whether the registry is ready to serve traffic by checking the
+// dependencies it needs
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+)
+
+// Status values used in readiness reports
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+)
+
+// Check is a named dependency check. Run returns nil when the dependency is usable.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// ComponentReport is the outcome of a single check
+type ComponentReport struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Report is the outcome of a readiness evaluation
+type Report struct {
+	Status       string                     `json:"status"`
+	ShuttingDown bool                       `json:"shutting_down,omitempty"`
+	CheckedAt    time.Time                  `json:"checked_at"`
+	Components   map[string]ComponentReport `json:"components"`
+}
+
+// Ready reports whether the registry should receive traffic
+func (r Report) Ready() bool {
+	return r.Status == StatusReady
+}
+
+// Readiness runs dependency checks and caches their results, so that frequent probes don't
+// put load on the dependencies themselves
+type Readiness struct {
+	checks   []Check
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	shuttingDown atomic.Bool
+
+	mu     sync.Mutex
+	cached *Report
+}
+
+// NewReadiness creates a readiness reporter. Each check is given timeout to complete, and
+// results are reused for cacheTTL.
+func NewReadiness(timeout, cacheTTL time.Duration, checks ...Check) *Readiness {
+	return &Readiness{checks: checks, timeout: timeout, cacheTTL: cacheTTL}
+}
+
+// Shutdown marks the registry as not ready from now on, so load balancers stop routing new
+// traffic to it while in-flight requests drain
+func (r *Readiness) Shutdown() {
+	r.shuttingDown.Store(true)
+}
+
+// Report returns the current readiness, running the checks if the cached result has expired
+func (r *Readiness) Report(ctx context.Context) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cached == nil || time.Since(r.cached.CheckedAt) >= r.cacheTTL {
+		// Results are shared between probes, so a cancelled request must not fail the checks
+		report := r.run(context.WithoutCancel(ctx))
+		r.cached = &report
+	}
+
+	report := *r.cached
+	if r.shuttingDown.Load() {
+		report.Status = StatusNotReady
+		report.ShuttingDown = true
+	}
+	return report
+}
+
+// run executes all checks in parallel
+func (r *Readiness) run(ctx context.Context) Report {
+	report := Report{
+		Status:     StatusReady,
+		CheckedAt:  time.Now(),
+		Components: make(map[string]ComponentReport, len(r.checks)),
+	}
+
+	results := make([]ComponentReport, len(r.checks))
+	var wg sync.WaitGroup
+	for i, check := range r.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	for i, check := range r.checks {
+		report.Components[check.Name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusNotReady
+		}
+	}
+	return report
+}
+
+// runCheck executes a single check, giving up once the timeout expires even if the check
+// itself doesn't honour its context
+func (r *Readiness) runCheck(ctx context.Context, check Check) ComponentReport {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- check.Run(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", r.timeout)
+	}
+
+	result := ComponentReport{Status: StatusOK, DurationMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Connector is implemented by databases that can report their connection state
+type Connector interface {
+	Connection() *database.ConnectionInfo
+}
+
+// DatabaseCheck checks that the database is connected
+func DatabaseCheck(db Connector) Check {
+	return Check{
+		Name: "database",
+		Run: func(_ context.Context) error {
+			if info := db.Connection(); info == nil || !info.IsConnected {
+				return errors.New("database is not connected")
+			}
+			return nil
+		},
+	}
+}
+
+// HTTPCheck checks that url can be reached. Any response below 500 counts as reachable.
+func HTTPCheck(name string, client *http.Client, url string) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			resp.Body.Close()
+			if resp.StatusCode >= http.StatusInternalServerError {
+				return fmt.Errorf("unexpected status %d", resp.StatusCode)
+			}
+			return nil
+		},
+	}
+}
+
+// Task tracks a one-off startup task, such as the seed import, that must finish before the
+// registry is ready
+type Task struct {
+	mu       sync.Mutex
+	finished bool
+	err      error
+}
+
+// NewTask creates a task that has not finished yet
+func NewTask() *Task {
+	return &Task{}
+}
+
+// Finish records that the task has finished, with err if it failed
+func (t *Task) Finish(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.finished = true
+	t.err = err
+}
+
+// Finished reports whether the task has finished, successfully or not
+func (t *Task) Finished() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.finished
+}
+
+// Check reports the task as failing until it has finished successfully
+func (t *Task) Check(name string) Check {
+	return Check{
+		Name: name,
+		Run: func(_ context.Context) error {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			switch {
+			case !t.finished:
+				return errors.New("in progress")
+			case t.err != nil:
+				return t.err
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,73 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/health"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadiness(t *testing.T) {
+	ok := health.Check{Name: "ok", Run: func(context.Context) error { return nil }}
+	failing := health.Check{Name: "failing", Run: func(context.Context) error { return errors.New("down") }}
+	hanging := health.Check{Name: "hanging", Run: func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}}
+
+	t.Run("all checks pass", func(t *testing.T) {
+		db := health.DatabaseCheck(database.NewMemoryDB(map[string]*model.Server{}))
+		report := health.NewReadiness(time.Second, 0, ok, db).Report(context.Background())
+		assert.True(t, report.Ready())
+		assert.Equal(t, health.StatusOK, report.Components["ok"].Status)
+		assert.Equal(t, health.StatusOK, report.Components["database"].Status)
+	})
+
+	t.Run("failing check", func(t *testing.T) {
+		report := health.NewReadiness(time.Second, 0, ok, failing).Report(context.Background())
+		assert.False(t, report.Ready())
+		assert.Equal(t, health.StatusNotReady, report.Status)
+		assert.Equal(t, "down", report.Components["failing"].Error)
+	})
+
+	t.Run("check timeout", func(t *testing.T) {
+		report := health.NewReadiness(10*time.Millisecond, 0, hanging).Report(context.Background())
+		assert.False(t, report.Ready())
+		assert.Contains(t, report.Components["hanging"].Error, "timed out")
+	})
+
+	t.Run("results are cached", func(t *testing.T) {
+		var calls atomic.Int32
+		counting := health.Check{Name: "counting", Run: func(context.Context) error {
+			calls.Add(1)
+			return nil
+		}}
+		readiness := health.NewReadiness(time.Second, time.Minute, counting)
+		readiness.Report(context.Background())
+		readiness.Report(context.Background())
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("shutting down", func(t *testing.T) {
+		readiness := health.NewReadiness(time.Second, time.Minute, ok)
+		assert.True(t, readiness.Report(context.Background()).Ready())
+		readiness.Shutdown()
+		report := readiness.Report(context.Background())
+		assert.False(t, report.Ready())
+		assert.True(t, report.ShuttingDown)
+	})
+
+	t.Run("startup task", func(t *testing.T) {
+		task := health.NewTask()
+		readiness := health.NewReadiness(time.Second, 0, task.Check("seed_import"))
+		assert.False(t, readiness.Report(context.Background()).Ready())
+		task.Finish(nil)
+		assert.True(t, readiness.Report(context.Background()).Ready())
+	})
+}
@@ -0,0 +1,151 @@
+// Package idempotency lets clients safely retry requests that create resources: the response to
+// a request sent with an Idempotency-Key header is stored, and a retry with the same key and
+// payload receives the stored response instead of being executed again
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/model"
+)
+
+// HeaderName is the request header carrying the client's key
+const HeaderName = "Idempotency-Key"
+
+// ReplayedHeaderName is the response header set on replayed responses
+const ReplayedHeaderName = "Idempotent-Replayed"
+
+// maxKeyLength bounds the size of stored keys
+const maxKeyLength = 255
+
+// reservationTTL bounds how long a request holds its key while in progress, so that a key
+// isn't blocked forever if the instance handling the request dies
+const reservationTTL = time.Minute
+
+var (
+	// ErrInvalidKey is returned for a key that is too long or contains non-printable characters
+	ErrInvalidKey = errors.New("invalid idempotency key")
+	// ErrKeyReused is returned when a key is sent again with a different request
+	ErrKeyReused = errors.New("idempotency key was already used for a different request")
+	// ErrInProgress is returned when a request with the same key is still being handled
+	ErrInProgress = errors.New("a request with this idempotency key is in progress")
+)
+
+// ValidateKey checks that key is 1 to 255 printable ASCII characters
+func ValidateKey(key string) error {
+	if key == "" || len(key) > maxKeyLength {
+		return fmt.Errorf("%w: must be 1 to %d characters", ErrInvalidKey, maxKeyLength)
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return fmt.Errorf("%w: must be printable ASCII", ErrInvalidKey)
+		}
+	}
+	return nil
+}
+
+// RequestHash digests the decoded request, so that retries which encode the same payload
+// differently, e.g. with another key order, are still recognized as identical
+func RequestHash(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Response is a stored response
+type Response struct {
+	StatusCode int
+	Body       []byte
+}
+
+// Store keeps the responses to idempotent requests in the database for a fixed window
+type Store struct {
+	db  database.Database
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewStore creates a store keeping responses for ttl. A ttl of zero or less disables it, so
+// that every request is executed.
+func NewStore(db database.Database, ttl time.Duration) *Store {
+	return &Store{db: db, ttl: ttl, now: time.Now}
+}
+
+// Reserve claims key, sent by scope, usually the authenticated identity, for the request with
+// requestHash before it is handled. It returns the stored response if the request was already
+// handled, ErrInProgress if a request with the key is still being handled, and ErrKeyReused if
+// the key was used for a request with another hash. Otherwise the key is reserved, and the
+// caller must either Complete or Release it.
+func (s *Store) Reserve(ctx context.Context, scope, key, requestHash string) (*Response, error) {
+	if s == nil || s.ttl <= 0 {
+		return nil, nil
+	}
+
+	// The unique key makes the reservation atomic: of concurrent requests with the same key,
+	// exactly one inserts the record and the others find it
+	now := s.now()
+	err := s.db.InsertIdempotencyRecord(ctx, &model.IdempotencyRecord{
+		Key:         recordKey(scope, key),
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(min(reservationTTL, s.ttl)),
+	})
+	if !errors.Is(err, database.ErrAlreadyExists) {
+		return nil, err
+	}
+
+	record, err := s.db.GetIdempotencyRecord(ctx, recordKey(scope, key))
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		// The other reservation expired in between; the client can try again
+		return nil, ErrInProgress
+	case err != nil:
+		return nil, err
+	case record.RequestHash != requestHash:
+		return nil, ErrKeyReused
+	case record.InProgress():
+		return nil, ErrInProgress
+	}
+	return &Response{StatusCode: record.StatusCode, Body: record.Body}, nil
+}
+
+// Complete stores the response to the request that reserved key, so that retries replay it
+func (s *Store) Complete(ctx context.Context, scope, key, requestHash string, response Response) error {
+	if s == nil || s.ttl <= 0 {
+		return nil
+	}
+
+	now := s.now()
+	return s.db.UpdateIdempotencyRecord(ctx, &model.IdempotencyRecord{
+		Key:         recordKey(scope, key),
+		RequestHash: requestHash,
+		StatusCode:  response.StatusCode,
+		Body:        response.Body,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	})
+}
+
+// Release gives up the reservation of key after a request failed, so that a retry is handled
+// again rather than replayed
+func (s *Store) Release(ctx context.Context, scope, key string) error {
+	if s == nil || s.ttl <= 0 {
+		return nil
+	}
+	return s.db.DeleteIdempotencyRecord(ctx, recordKey(scope, key))
+}
+
+// recordKey scopes key so that clients can't see or collide with each other's keys
+func recordKey(scope, key string) string {
+	return scope + ":" + key
+}
@@ -0,0 +1,81 @@
+package idempotency_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/idempotency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateKey(t *testing.T) {
+	assert.NoError(t, idempotency.ValidateKey("ci-run-4821/publish"))
+	for _, key := range []string{"", strings.Repeat("k", 256), "tab\tkey", "ключ"} {
+		assert.ErrorIs(t, idempotency.ValidateKey(key), idempotency.ErrInvalidKey, key)
+	}
+}
+
+func TestRequestHash(t *testing.T) {
+	a, err := idempotency.RequestHash(map[string]any{"name": "a", "version": "1"})
+	require.NoError(t, err)
+	b, err := idempotency.RequestHash(map[string]any{"version": "1", "name": "a"})
+	require.NoError(t, err)
+	c, err := idempotency.RequestHash(map[string]any{"name": "a", "version": "2"})
+	require.NoError(t, err)
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := idempotency.NewStore(database.NewMemoryDB(nil), time.Hour)
+	response := idempotency.Response{StatusCode: 201, Body: []byte(`{"id":"1"}`)}
+
+	replay, err := store.Reserve(ctx, "github:octocat", "key", "hash")
+	require.NoError(t, err)
+	assert.Nil(t, replay)
+
+	// A concurrent retry finds the reservation until the first request completes
+	_, err = store.Reserve(ctx, "github:octocat", "key", "hash")
+	assert.ErrorIs(t, err, idempotency.ErrInProgress)
+
+	require.NoError(t, store.Complete(ctx, "github:octocat", "key", "hash", response))
+
+	replay, err = store.Reserve(ctx, "github:octocat", "key", "hash")
+	require.NoError(t, err)
+	assert.Equal(t, &response, replay)
+
+	_, err = store.Reserve(ctx, "github:octocat", "key", "other")
+	assert.ErrorIs(t, err, idempotency.ErrKeyReused)
+
+	// Keys are scoped to the identity that sent them
+	replay, err = store.Reserve(ctx, "github:someone", "key", "hash")
+	require.NoError(t, err)
+	assert.Nil(t, replay)
+
+	t.Run("released", func(t *testing.T) {
+		_, err := store.Reserve(ctx, "github:octocat", "failed", "hash")
+		require.NoError(t, err)
+		require.NoError(t, store.Release(ctx, "github:octocat", "failed"))
+
+		// After a failure the retry is handled again
+		replay, err := store.Reserve(ctx, "github:octocat", "failed", "hash")
+		require.NoError(t, err)
+		assert.Nil(t, replay)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		store := idempotency.NewStore(database.NewMemoryDB(nil), 0)
+		for range 2 {
+			replay, err := store.Reserve(ctx, "github:octocat", "key", "hash")
+			require.NoError(t, err)
+			assert.Nil(t, replay)
+		}
+		require.NoError(t, store.Complete(ctx, "github:octocat", "key", "hash", response))
+	})
+}
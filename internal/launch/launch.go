@@ -0,0 +1,310 @@
+// Package launch resolves a package's declared inputs against user-supplied values to produce
+// the command line and environment that start the server
+package launch
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/internal/model"
+)
+
+var (
+	// ErrNoRunner is returned for a package whose registry has no known runner and which has
+	// no runtime hint
+	ErrNoRunner = errors.New("no known runner for package")
+	// ErrMissing is reported for a required input with no value and no default
+	ErrMissing = errors.New("value is required")
+	// ErrInvalidFormat is reported for a value that doesn't match the input's format
+	ErrInvalidFormat = errors.New("value does not match format")
+	// ErrNotAChoice is reported for a value outside the input's choices
+	ErrNotAChoice = errors.New("value is not one of the allowed choices")
+	// ErrUnknownInput is reported for a supplied value that no input of the package uses
+	ErrUnknownInput = errors.New("unknown input")
+)
+
+// InputError describes a problem with the value of a single input
+type InputError struct {
+	// Key identifies the input, as used in the values map
+	Key string
+	Err error
+}
+
+func (e *InputError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Key, e.Err)
+}
+
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// Command is a resolved server invocation
+type Command struct {
+	Command string
+	Args    []string
+	Env     map[string]string
+}
+
+// Argv returns the command followed by its arguments
+func (c *Command) Argv() []string {
+	return append([]string{c.Command}, c.Args...)
+}
+
+// runners maps package registries to the command that fetches and runs their packages
+var runners = map[string]string{
+	"npm":    "npx",
+	"pypi":   "uvx",
+	"docker": "docker",
+}
+
+// Runner returns the command that runs pkg and the arguments that come before its runtime
+// arguments. envNames are the environment variables the server receives, which a container
+// runtime has to pass through explicitly.
+func Runner(pkg model.Package, envNames []string) (string, []string, error) {
+	command := pkg.RunTimeHint
+	if command == "" {
+		command = runners[pkg.RegistryName]
+	}
+	if command == "" {
+		return "", nil, fmt.Errorf("%w: registry %q", ErrNoRunner, pkg.RegistryName)
+	}
+
+	var args []string
+	switch pkg.RegistryName {
+	case "npm":
+		args = append(args, "-y")
+	case "docker":
+		args = append(args, "run", "-i", "--rm")
+		for _, name := range envNames {
+			args = append(args, "-e", name)
+		}
+	}
+	return command, args, nil
+}
+
+// PackageReference pins the package version in the syntax of its registry
+func PackageReference(pkg model.Package) string {
+	if pkg.Version == "" {
+		return pkg.Name
+	}
+	switch pkg.RegistryName {
+	case "pypi":
+		return pkg.Name + "==" + pkg.Version
+	case "docker":
+		return pkg.Name + ":" + pkg.Version
+	default:
+		return pkg.Name + "@" + pkg.Version
+	}
+}
+
+// ArgumentList names the argument list of a package that an argument belongs to
+type ArgumentList string
+
+const (
+	RuntimeArguments ArgumentList = "runtime_arg"
+	PackageArguments ArgumentList = "package_arg"
+)
+
+// ArgumentKey identifies an argument in the values map: its value hint, else its name without
+// leading dashes, else its list and 1-based position in that list, e.g. package_arg_1
+func ArgumentKey(list ArgumentList, arg model.Argument, position int) string {
+	if arg.ValueHint != "" {
+		return arg.ValueHint
+	}
+	if name := strings.TrimLeft(arg.Name, "-"); name != "" {
+		return name
+	}
+	return fmt.Sprintf("%s_%d", list, position)
+}
+
+// Resolve builds the invocation of pkg from values, keyed by environment variable name,
+// ArgumentKey, or template variable name. Every problem with the values is reported, joined
+// into the returned error as *InputError values.
+func Resolve(pkg model.Package, values map[string]string) (*Command, error) {
+	r := &resolver{values: values, used: map[string]bool{}, looked: map[string]lookedUp{}}
+
+	env := make(map[string]string, len(pkg.EnvironmentVariables))
+	for _, variable := range pkg.EnvironmentVariables {
+		if value, ok := r.resolve(variable.Name, variable.InputWithVariables); ok {
+			env[variable.Name] = value
+		}
+	}
+	command, args, err := Runner(pkg, sortedKeys(env))
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, r.arguments(RuntimeArguments, pkg.RuntimeArguments)...)
+	args = append(args, PackageReference(pkg))
+	args = append(args, r.arguments(PackageArguments, pkg.PackageArguments)...)
+
+	for _, key := range sortedKeys(values) {
+		if !r.used[key] {
+			r.fail(key, ErrUnknownInput)
+		}
+	}
+	if len(r.errs) > 0 {
+		return nil, errors.Join(r.errs...)
+	}
+	return &Command{Command: command, Args: args, Env: env}, nil
+}
+
+// resolver accumulates errors and tracks which values were consumed
+type resolver struct {
+	values map[string]string
+	used   map[string]bool
+	looked map[string]lookedUp
+	errs   []error
+}
+
+// lookedUp remembers the outcome of a lookup, so a variable referenced several times is
+// validated and reported once
+type lookedUp struct {
+	value string
+	ok    bool
+}
+
+func (r *resolver) fail(key string, err error) {
+	r.errs = append(r.errs, &InputError{Key: key, Err: err})
+}
+
+func (r *resolver) arguments(list ArgumentList, arguments []model.Argument) []string {
+	var args []string
+	for i, arg := range arguments {
+		value, ok := r.resolve(ArgumentKey(list, arg, i+1), arg.InputWithVariables)
+		if arg.Type != model.ArgumentTypeNamed {
+			if ok {
+				args = append(args, value)
+			}
+			continue
+		}
+
+		switch {
+		case arg.Format == model.FormatBoolean && arg.Value == "":
+			// A boolean flag is passed alone when set and left out otherwise
+			if ok && value == "true" {
+				args = append(args, arg.Name)
+			}
+		case ok:
+			args = append(args, arg.Name, value)
+		default:
+			// Optional flags without a value are left out; only boolean flags are passed alone
+		}
+	}
+	return args
+}
+
+// resolve returns the final value of an input. A fixed Value, or else a Template, is expanded
+// with the input's variables and properties; otherwise the user's value or the default is used.
+// It reports false when the input is optional and has no value.
+func (r *resolver) resolve(key string, input model.InputWithVariables) (string, bool) {
+	pattern := input.Value
+	if pattern == "" {
+		pattern = input.Template
+	}
+	if pattern == "" {
+		return r.lookup(key, input.Input)
+	}
+
+	variables := make(map[string]model.Input, len(input.Properties)+len(input.Variables))
+	for name, variable := range input.Properties {
+		variables[name] = variable
+	}
+	for name, variable := range input.Variables {
+		variables[name] = variable
+	}
+
+	failures := len(r.errs)
+	complete := true
+	expanded := model.TemplateVariable.ReplaceAllStringFunc(pattern, func(match string) string {
+		name := match[1 : len(match)-1]
+		variable, ok := variables[name]
+		if !ok {
+			// Braces that aren't a declared variable are literal text
+			return match
+		}
+		value, ok := r.lookup(name, variable)
+		if !ok {
+			complete = false
+		}
+		return value
+	})
+	// An input whose template can't be completed is left out, which is only an error of its
+	// own when it is required and the missing variables weren't reported already
+	if !complete {
+		if input.IsRequired && len(r.errs) == failures {
+			r.fail(key, ErrMissing)
+		}
+		return "", false
+	}
+	return expanded, true
+}
+
+// lookup returns the user's value for key, or the input's default, after validating it
+func (r *resolver) lookup(key string, input model.Input) (string, bool) {
+	if result, ok := r.looked[key]; ok {
+		return result.value, result.ok
+	}
+	value, ok := r.lookupOnce(key, input)
+	r.looked[key] = lookedUp{value: value, ok: ok}
+	return value, ok
+}
+
+func (r *resolver) lookupOnce(key string, input model.Input) (string, bool) {
+	value, supplied := r.values[key]
+	if supplied {
+		r.used[key] = true
+	}
+	if !supplied || value == "" {
+		value = input.Default
+	}
+	if value == "" {
+		if input.IsRequired {
+			r.fail(key, ErrMissing)
+		}
+		return "", false
+	}
+
+	if err := validate(value, input); err != nil {
+		r.fail(key, err)
+		return "", false
+	}
+	return value, true
+}
+
+// validate checks value against the input's format and choices
+func validate(value string, input model.Input) error {
+	if len(input.Choices) > 0 && !slices.Contains(input.Choices, value) {
+		return fmt.Errorf("%w: %q not in [%s]", ErrNotAChoice, value, strings.Join(input.Choices, ", "))
+	}
+
+	var err error
+	switch input.Format {
+	case model.FormatNumber:
+		_, err = strconv.ParseFloat(value, 64)
+	case model.FormatBoolean:
+		if value != "true" && value != "false" {
+			err = errors.New("expected true or false")
+		}
+	case model.FormatFilePath:
+		if strings.ContainsRune(value, 0) {
+			err = errors.New("contains a NUL byte")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%w %s: %q", ErrInvalidFormat, input.Format, value)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,131 @@
+package launch_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/launch"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func input(in model.Input) model.InputWithVariables {
+	return model.InputWithVariables{Input: in}
+}
+
+func TestResolve(t *testing.T) {
+	pkg := model.Package{
+		RegistryName: "npm",
+		Name:         "@example/weather",
+		Version:      "1.2.0",
+		RuntimeArguments: []model.Argument{{
+			Type:               model.ArgumentTypeNamed,
+			Name:               "--max-old-space-size",
+			InputWithVariables: input(model.Input{Format: model.FormatNumber, Default: "512"}),
+		}},
+		PackageArguments: []model.Argument{
+			{
+				Type:               model.ArgumentTypeNamed,
+				Name:               "--verbose",
+				InputWithVariables: input(model.Input{Format: model.FormatBoolean}),
+			},
+			{
+				// Unset optional flags are left out rather than passed without their value
+				Type:               model.ArgumentTypeNamed,
+				Name:               "--log-file",
+				InputWithVariables: input(model.Input{Format: model.FormatFilePath}),
+			},
+			{
+				Type:               model.ArgumentTypePositional,
+				ValueHint:          "region",
+				InputWithVariables: input(model.Input{IsRequired: true, Choices: []string{"eu", "us"}}),
+			},
+		},
+		EnvironmentVariables: []model.KeyValueInput{
+			{
+				Name: "WEATHER_API_KEY",
+				InputWithVariables: model.InputWithVariables{
+					Input: model.Input{Template: "Bearer {token}", IsRequired: true},
+					Variables: map[string]model.Input{
+						"token": {IsRequired: true, IsSecret: true},
+					},
+				},
+			},
+			{
+				Name:               "WEATHER_CACHE_DIR",
+				InputWithVariables: input(model.Input{Format: model.FormatFilePath}),
+			},
+		},
+	}
+
+	t.Run("valid values", func(t *testing.T) {
+		command, err := launch.Resolve(pkg, map[string]string{
+			"verbose": "true",
+			"region":  "eu",
+			"token":   "s3cret",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"npx", "-y", "--max-old-space-size", "512", "@example/weather@1.2.0", "--verbose", "eu",
+		}, command.Argv())
+		assert.Equal(t, map[string]string{"WEATHER_API_KEY": "Bearer s3cret"}, command.Env)
+	})
+
+	t.Run("every problem is reported", func(t *testing.T) {
+		_, err := launch.Resolve(pkg, map[string]string{
+			"max-old-space-size": "lots",
+			"region":             "asia",
+			"verbose":            "yes",
+			"regoin":             "eu",
+		})
+		require.Error(t, err)
+
+		problems := map[string]error{}
+		for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+			var inputErr *launch.InputError
+			require.True(t, errors.As(e, &inputErr))
+			problems[inputErr.Key] = inputErr.Err
+		}
+		assert.Len(t, problems, 5)
+		assert.ErrorIs(t, problems["token"], launch.ErrMissing)
+		assert.ErrorIs(t, problems["max-old-space-size"], launch.ErrInvalidFormat)
+		assert.ErrorIs(t, problems["verbose"], launch.ErrInvalidFormat)
+		assert.ErrorIs(t, problems["region"], launch.ErrNotAChoice)
+		assert.ErrorIs(t, problems["regoin"], launch.ErrUnknownInput)
+	})
+
+	t.Run("docker passes environment through", func(t *testing.T) {
+		command, err := launch.Resolve(model.Package{
+			RegistryName: "docker",
+			Name:         "example/db",
+			Version:      "2",
+			EnvironmentVariables: []model.KeyValueInput{
+				{Name: "DB_URL", InputWithVariables: input(model.Input{Value: "postgres://db/app"})},
+			},
+		}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"docker", "run", "-i", "--rm", "-e", "DB_URL", "example/db:2"}, command.Argv())
+	})
+
+	t.Run("unnamed positional arguments are keyed by list", func(t *testing.T) {
+		command, err := launch.Resolve(model.Package{
+			RegistryName: "docker",
+			Name:         "example/db",
+			Version:      "2",
+			RuntimeArguments: []model.Argument{
+				{Type: model.ArgumentTypePositional, InputWithVariables: input(model.Input{IsRequired: true})},
+			},
+			PackageArguments: []model.Argument{
+				{Type: model.ArgumentTypePositional, InputWithVariables: input(model.Input{IsRequired: true})},
+			},
+		}, map[string]string{"runtime_arg_1": "--network=host", "package_arg_1": "serve"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"docker", "run", "-i", "--rm", "--network=host", "example/db:2", "serve"}, command.Argv())
+	})
+
+	t.Run("unknown runner", func(t *testing.T) {
+		_, err := launch.Resolve(model.Package{RegistryName: "unknown", Name: "x"}, nil)
+		require.ErrorIs(t, err, launch.ErrNoRunner)
+	})
+}
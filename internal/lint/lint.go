@@ -0,0 +1,212 @@
+// Package lint checks the input definitions of a server entry for mistakes that a JSON schema
+// can't express, such as templates that reference undeclared variables
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/internal/model"
+)
+
+// Severity says whether a finding blocks publishing
+type Severity string
+
+const (
+	// SeverityError findings make the entry unusable and are rejected
+	SeverityError Severity = "error"
+	// SeverityWarning findings are suspicious but accepted
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is a single problem, located by a JSON pointer into the published document
+type Finding struct {
+	Pointer  string   `json:"pointer"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// HasErrors reports whether any of the findings is an error
+func HasErrors(findings []Finding) bool {
+	return slices.ContainsFunc(findings, func(f Finding) bool { return f.Severity == SeverityError })
+}
+
+// ServerDetail lints every input of the server's packages and remotes
+func ServerDetail(server *model.ServerDetail) []Finding {
+	l := &linter{}
+	for i, pkg := range server.Packages {
+		base := pointer("/packages", strconv.Itoa(i))
+		for j, arg := range pkg.RuntimeArguments {
+			l.argument(pointer(base, "runtime_arguments", strconv.Itoa(j)), arg)
+		}
+		for j, arg := range pkg.PackageArguments {
+			l.argument(pointer(base, "package_arguments", strconv.Itoa(j)), arg)
+		}
+		for j, variable := range pkg.EnvironmentVariables {
+			l.keyValueInput(pointer(base, "environment_variables", strconv.Itoa(j)), variable, "environment variable")
+		}
+	}
+	for i, remote := range server.Remotes {
+		for j, header := range remote.Headers {
+			l.keyValueInput(pointer("/remotes", strconv.Itoa(i), "headers", strconv.Itoa(j)), header, "header")
+		}
+	}
+	return l.findings
+}
+
+// Argument lints a runtime or package argument located at ptr
+func Argument(ptr string, arg model.Argument) []Finding {
+	l := &linter{}
+	l.argument(ptr, arg)
+	return l.findings
+}
+
+// KeyValueInput lints an environment variable located at ptr
+func KeyValueInput(ptr string, input model.KeyValueInput) []Finding {
+	l := &linter{}
+	l.keyValueInput(ptr, input, "environment variable")
+	return l.findings
+}
+
+// Input lints an input located at ptr
+func Input(ptr string, input model.Input) []Finding {
+	l := &linter{}
+	l.input(ptr, input, nil)
+	return l.findings
+}
+
+// linter accumulates findings
+type linter struct {
+	findings []Finding
+}
+
+func (l *linter) report(ptr string, severity Severity, format string, args ...any) {
+	l.findings = append(l.findings, Finding{Pointer: ptr, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) argument(ptr string, arg model.Argument) {
+	switch arg.Type {
+	case model.ArgumentTypeNamed:
+		if arg.Name == "" {
+			l.report(pointer(ptr, "name"), SeverityError, "named argument has no name")
+		}
+	case model.ArgumentTypePositional:
+	default:
+		l.report(pointer(ptr, "type"), SeverityError, "argument type %q is not %q or %q",
+			arg.Type, model.ArgumentTypePositional, model.ArgumentTypeNamed)
+	}
+	l.inputWithVariables(ptr, arg.InputWithVariables)
+}
+
+// keyValueInput lints an environment variable or header; kind names which one for messages
+func (l *linter) keyValueInput(ptr string, input model.KeyValueInput, kind string) {
+	if input.Name == "" {
+		l.report(pointer(ptr, "name"), SeverityError, "%s has no name", kind)
+	}
+	l.inputWithVariables(ptr, input.InputWithVariables)
+}
+
+func (l *linter) inputWithVariables(ptr string, input model.InputWithVariables) {
+	l.input(ptr, input.Input, input.Variables)
+	for _, name := range sortedKeys(input.Variables) {
+		l.input(pointer(ptr, "variables", name), input.Variables[name], nil)
+	}
+}
+
+// input lints input and the template references in its value and template against the
+// declared variables and properties
+func (l *linter) input(ptr string, input model.Input, variables map[string]model.Input) {
+	switch input.Format {
+	case "", model.FormatString, model.FormatNumber, model.FormatBoolean, model.FormatFilePath:
+	default:
+		l.report(pointer(ptr, "format"), SeverityError, "unknown format %q", input.Format)
+	}
+
+	for i, choice := range input.Choices {
+		if msg := checkFormat(choice, input.Format); msg != "" {
+			l.report(pointer(ptr, "choices", strconv.Itoa(i)), SeverityError, "choice %s", msg)
+		}
+	}
+
+	if input.Default != "" {
+		defaultPtr := pointer(ptr, "default")
+		if msg := checkFormat(input.Default, input.Format); msg != "" {
+			l.report(defaultPtr, SeverityError, "default %s", msg)
+		}
+		if len(input.Choices) > 0 && !slices.Contains(input.Choices, input.Default) {
+			l.report(defaultPtr, SeverityError, "default %q is not one of the choices", input.Default)
+		}
+		if input.IsSecret {
+			l.report(defaultPtr, SeverityWarning, "secret input has a default, which is published in the clear")
+		}
+	}
+
+	if input.IsSecret && input.Format == model.FormatFilePath {
+		l.report(pointer(ptr, "is_secret"), SeverityWarning, "file_path input is marked secret; secret the file's contents instead")
+	}
+
+	declared := make(map[string]bool, len(variables)+len(input.Properties))
+	for name := range variables {
+		declared[name] = true
+	}
+	for name := range input.Properties {
+		declared[name] = true
+	}
+	referenced := map[string]bool{}
+	for _, field := range []struct{ name, value string }{{"value", input.Value}, {"template", input.Template}} {
+		for _, match := range model.TemplateVariable.FindAllStringSubmatch(field.value, -1) {
+			name := match[1]
+			referenced[name] = true
+			if !declared[name] {
+				l.report(pointer(ptr, field.name), SeverityError, "references undeclared variable {%s}", name)
+			}
+		}
+	}
+	for _, name := range sortedKeys(variables) {
+		if !referenced[name] {
+			l.report(pointer(ptr, "variables", name), SeverityWarning, "variable is never referenced")
+		}
+	}
+
+	for _, name := range sortedKeys(input.Properties) {
+		l.input(pointer(ptr, "properties", name), input.Properties[name], nil)
+	}
+}
+
+// checkFormat describes how value fails to match format, or returns "" if it matches
+func checkFormat(value string, format model.Format) string {
+	switch format {
+	case model.FormatNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Sprintf("%q is not a number", value)
+		}
+	case model.FormatBoolean:
+		if value != "true" && value != "false" {
+			return fmt.Sprintf("%q is not true or false", value)
+		}
+	}
+	return ""
+}
+
+// pointer appends reference tokens to a JSON pointer, escaping them as RFC 6901 requires
+func pointer(base string, tokens ...string) string {
+	var b strings.Builder
+	b.WriteString(base)
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,62 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/lint"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerDetail(t *testing.T) {
+	server := &model.ServerDetail{
+		Packages: []model.Package{{
+			RuntimeArguments: []model.Argument{{
+				Type: model.ArgumentTypeNamed,
+				InputWithVariables: model.InputWithVariables{
+					Input: model.Input{Value: "--port={port}/{host}"},
+					Variables: map[string]model.Input{
+						"port":   {Format: model.FormatNumber, Default: "eighty"},
+						"unused": {},
+					},
+				},
+			}},
+			EnvironmentVariables: []model.KeyValueInput{{
+				Name: "CONFIG",
+				InputWithVariables: model.InputWithVariables{Input: model.Input{
+					Format:   model.FormatFilePath,
+					IsSecret: true,
+				}},
+			}},
+		}},
+		Remotes: []model.Remote{{
+			Headers: []model.KeyValueInput{{
+				InputWithVariables: model.InputWithVariables{Input: model.Input{Default: "b", Choices: []string{"a"}}},
+			}},
+		}},
+	}
+
+	assert.Equal(t, []lint.Finding{
+		{Pointer: "/packages/0/runtime_arguments/0/name", Severity: lint.SeverityError, Message: "named argument has no name"},
+		{Pointer: "/packages/0/runtime_arguments/0/value", Severity: lint.SeverityError, Message: "references undeclared variable {host}"},
+		{Pointer: "/packages/0/runtime_arguments/0/variables/unused", Severity: lint.SeverityWarning, Message: "variable is never referenced"},
+		{Pointer: "/packages/0/runtime_arguments/0/variables/port/default", Severity: lint.SeverityError, Message: `default "eighty" is not a number`},
+		{Pointer: "/packages/0/environment_variables/0/is_secret", Severity: lint.SeverityWarning,
+			Message: "file_path input is marked secret; secret the file's contents instead"},
+		{Pointer: "/remotes/0/headers/0/name", Severity: lint.SeverityError, Message: "header has no name"},
+		{Pointer: "/remotes/0/headers/0/default", Severity: lint.SeverityError, Message: `default "b" is not one of the choices`},
+	}, lint.ServerDetail(server))
+}
+
+func TestPointerEscaping(t *testing.T) {
+	findings := lint.KeyValueInput("/env", model.KeyValueInput{
+		Name: "X",
+		InputWithVariables: model.InputWithVariables{
+			Input:     model.Input{Value: "{a/b~c}"},
+			Variables: map[string]model.Input{"a/b~c": {}},
+		},
+	})
+	// The regexp only allows [A-Za-z0-9_.-] in references, so the variable is never referenced
+	assert.Equal(t, "/env/variables/a~1b~0c", findings[0].Pointer)
+	assert.False(t, lint.HasErrors(findings))
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/model"
+)
+
+// instrumentedAuth counts the outcomes of credential validations by the wrapped service
+type instrumentedAuth struct {
+	auth.Service
+}
+
+// InstrumentAuth wraps svc so that ValidateAuth and Authenticate outcomes are counted,
+// broken down by model.AuthMethod
+func InstrumentAuth(svc auth.Service) auth.Service {
+	return &instrumentedAuth{Service: svc}
+}
+
+func (a *instrumentedAuth) ValidateAuth(ctx context.Context, authentication model.Authentication) (*auth.Identity, error) {
+	identity, err := a.Service.ValidateAuth(ctx, authentication)
+	recordAuth(authentication.Method, "validate", identity, err)
+	return identity, err
+}
+
+func (a *instrumentedAuth) Authenticate(ctx context.Context, authentication model.Authentication) (*auth.Identity, error) {
+	identity, err := a.Service.Authenticate(ctx, authentication)
+	recordAuth(authentication.Method, "authenticate", identity, err)
+	return identity, err
+}
+
+// recordAuth counts a single validation outcome
+func recordAuth(method model.AuthMethod, operation string, identity *auth.Identity, err error) {
+	var outcome string
+	switch {
+	case err == nil && identity != nil:
+		outcome = "success"
+	case errors.Is(err, auth.ErrAuthRequired):
+		outcome = "missing_credentials"
+	case errors.Is(err, auth.ErrUnsupportedAuthMethod):
+		outcome = "unsupported_method"
+	default:
+		outcome = "failure"
+	}
+
+	if method == "" {
+		method = model.AuthMethodNone
+	}
+	authValidationsTotal.WithLabelValues(string(method), operation, outcome).Inc()
+}
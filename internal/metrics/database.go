@@ -0,0 +1,184 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/projection"
+)
+
+// instrumentedDatabase records the latency of every operation of the wrapped database
+type instrumentedDatabase struct {
+	db      database.Database
+	backend string
+}
+
+// InstrumentDatabase wraps db so that each operation's latency is recorded, labelled with the
+// backend name and the method called. Seed imports additionally record import statistics.
+func InstrumentDatabase(db database.Database, backend string) database.Database {
+	return &instrumentedDatabase{db: db, backend: backend}
+}
+
+// observe records the duration of an operation that started at start and ended with err
+func (d *instrumentedDatabase) observe(operation string, start time.Time, err error) {
+	dbOperationDuration.WithLabelValues(d.backend, operation, dbOutcome(err)).Observe(time.Since(start).Seconds())
+}
+
+// dbOutcome classifies an operation error so that expected misses aren't counted as failures
+func dbOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, database.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, database.ErrAlreadyExists), errors.Is(err, database.ErrInvalidVersion),
+		errors.Is(err, database.ErrInvalidInput):
+		return "rejected"
+	default:
+		return "error"
+	}
+}
+
+func (d *instrumentedDatabase) ListWithOptions(
+	ctx context.Context, filter map[string]interface{}, opts database.ListOptions,
+) (*database.ListResult, error) {
+	start := time.Now()
+	result, err := d.db.ListWithOptions(ctx, filter, opts)
+	d.observe("list_with_options", start, err)
+	return result, err
+}
+
+func (d *instrumentedDatabase) GetByID(ctx context.Context, id string) (*model.ServerDetail, error) {
+	start := time.Now()
+	detail, err := d.db.GetByID(ctx, id)
+	d.observe("get_by_id", start, err)
+	return detail, err
+}
+
+func (d *instrumentedDatabase) GetByIDProjected(
+	ctx context.Context, id string, p projection.Projection,
+) (*model.ServerDetail, error) {
+	start := time.Now()
+	detail, err := d.db.GetByIDProjected(ctx, id, p)
+	d.observe("get_by_id_projected", start, err)
+	return detail, err
+}
+
+func (d *instrumentedDatabase) BatchGet(
+	ctx context.Context, ids []string, refs []database.ServerRef,
+) ([]*model.ServerDetail, error) {
+	start := time.Now()
+	details, err := d.db.BatchGet(ctx, ids, refs)
+	d.observe("batch_get", start, err)
+	return details, err
+}
+
+func (d *instrumentedDatabase) Publish(
+	ctx context.Context, serverDetail *model.ServerDetail, auditFn database.AuditFunc,
+) error {
+	start := time.Now()
+	err := d.db.Publish(ctx, serverDetail, auditFn)
+	d.observe("publish", start, err)
+	return err
+}
+
+func (d *instrumentedDatabase) ImportSeed(
+	ctx context.Context, seedFilePath string, auditFn database.SeedAuditFunc,
+) (database.ImportStats, error) {
+	start := time.Now()
+	stats, err := d.db.ImportSeed(ctx, seedFilePath, auditFn)
+	d.observe("import_seed", start, err)
+
+	seedImportDuration.Set(time.Since(start).Seconds())
+	seedImportServers.WithLabelValues("total").Set(float64(stats.Total))
+	seedImportServers.WithLabelValues("created").Set(float64(stats.Created))
+	seedImportServers.WithLabelValues("updated").Set(float64(stats.Updated))
+	seedImportServers.WithLabelValues("unchanged").Set(float64(stats.Unchanged))
+	seedImportServers.WithLabelValues("skipped").Set(float64(stats.Skipped))
+	seedImportServers.WithLabelValues("failed").Set(float64(stats.Failed))
+	if err != nil {
+		seedImportsTotal.WithLabelValues("failure").Inc()
+	} else {
+		seedImportsTotal.WithLabelValues("success").Inc()
+		seedImportLastSuccess.SetToCurrentTime()
+	}
+
+	return stats, err
+}
+
+// Export observes the time taken to open the snapshot, not the time taken to stream it
+func (d *instrumentedDatabase) Export(ctx context.Context) (database.ServerIterator, error) {
+	start := time.Now()
+	it, err := d.db.Export(ctx)
+	d.observe("export", start, err)
+	return it, err
+}
+
+func (d *instrumentedDatabase) InsertAuditRecord(ctx context.Context, record *model.AuditRecord) error {
+	start := time.Now()
+	err := d.db.InsertAuditRecord(ctx, record)
+	d.observe("insert_audit_record", start, err)
+	return err
+}
+
+func (d *instrumentedDatabase) ListAuditRecords(
+	ctx context.Context, filter database.AuditFilter, cursor string, limit int,
+) ([]*model.AuditRecord, string, error) {
+	start := time.Now()
+	records, next, err := d.db.ListAuditRecords(ctx, filter, cursor, limit)
+	d.observe("list_audit_records", start, err)
+	return records, next, err
+}
+
+func (d *instrumentedDatabase) InsertIdempotencyRecord(ctx context.Context, record *model.IdempotencyRecord) error {
+	start := time.Now()
+	err := d.db.InsertIdempotencyRecord(ctx, record)
+	d.observe("insert_idempotency_record", start, err)
+	return err
+}
+
+func (d *instrumentedDatabase) GetIdempotencyRecord(ctx context.Context, key string) (*model.IdempotencyRecord, error) {
+	start := time.Now()
+	record, err := d.db.GetIdempotencyRecord(ctx, key)
+	d.observe("get_idempotency_record", start, err)
+	return record, err
+}
+
+func (d *instrumentedDatabase) UpdateIdempotencyRecord(ctx context.Context, record *model.IdempotencyRecord) error {
+	start := time.Now()
+	err := d.db.UpdateIdempotencyRecord(ctx, record)
+	d.observe("update_idempotency_record", start, err)
+	return err
+}
+
+func (d *instrumentedDatabase) DeleteIdempotencyRecord(ctx context.Context, key string) error {
+	start := time.Now()
+	err := d.db.DeleteIdempotencyRecord(ctx, key)
+	d.observe("delete_idempotency_record", start, err)
+	return err
+}
+
+func (d *instrumentedDatabase) IncrementCounter(
+	ctx context.Context, key string, window time.Duration,
+) (int, time.Time, error) {
+	start := time.Now()
+	count, resetAt, err := d.db.IncrementCounter(ctx, key, window)
+	d.observe("increment_counter", start, err)
+	return count, resetAt, err
+}
+
+func (d *instrumentedDatabase) DecrementCounter(
+	ctx context.Context, key string, window time.Duration, resetAt time.Time,
+) error {
+	start := time.Now()
+	err := d.db.DecrementCounter(ctx, key, window, resetAt)
+	d.observe("decrement_counter", start, err)
+	return err
+}
+
+func (d *instrumentedDatabase) Close() error {
+	return d.db.Close()
+}
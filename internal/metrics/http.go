@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
+)
+
+// unmatchedRoute labels requests that did not match any registered route, so that
+// arbitrary paths can't blow up the label cardinality
+const unmatchedRoute = "unmatched"
+
+// Middleware records a request counter and latency histogram for every request, labelled
+// with the matched route pattern rather than the raw path
+func Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: w}
+
+			next.ServeHTTP(sw, r)
+
+			// ServeMux sets the pattern on the request once it has matched a route
+			route := r.Pattern
+			if route == "" {
+				route = unmatchedRoute
+			}
+			status := strconv.Itoa(sw.Status())
+
+			httpRequestsTotal.WithLabelValues(route, r.Method, status).Inc()
+			httpRequestDuration.WithLabelValues(route, r.Method, status).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// InstrumentPublish wraps the publish handler and counts its outcomes. A 201 response is
+// counted as "success"; error responses are counted by their problem code.
+func InstrumentPublish(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w}
+
+		next.ServeHTTP(sw, r)
+
+		outcome := "success"
+		switch {
+		case sw.code != "":
+			outcome = string(sw.code)
+		case sw.Status() != http.StatusCreated:
+			outcome = "status_" + strconv.Itoa(sw.Status())
+		}
+		publishTotal.WithLabelValues(outcome).Inc()
+	})
+}
+
+// statusWriter records the response status code and the problem code of error responses
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	code   problem.Code
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// RecordProblemCode implements problem.CodeRecorder
+func (w *statusWriter) RecordProblemCode(code problem.Code) {
+	w.code = code
+}
+
+// Status returns the response status code, defaulting to 200 if the handler wrote nothing
+func (w *statusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,95 @@
+// Package metrics exposes Prometheus metrics for the registry
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "mcp_registry"
+
+// registry holds all registry metrics. A private registry keeps the exposed metrics
+// independent of anything registered globally by dependencies.
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Total number of HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	publishTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "publish_total",
+		Help:      "Total number of publish requests by outcome. Failures are labelled with their problem code.",
+	}, []string{"outcome"})
+
+	dbOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_operation_duration_seconds",
+		Help:      "Database operation latency by backend, operation and outcome.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"backend", "operation", "outcome"})
+
+	seedImportsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "seed_imports_total",
+		Help:      "Total number of seed imports by outcome.",
+	}, []string{"outcome"})
+
+	seedImportServers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "seed_import_servers",
+		Help:      "Number of servers processed by the last seed import, by result.",
+	}, []string{"result"})
+
+	seedImportDuration = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "seed_import_duration_seconds",
+		Help:      "Duration of the last seed import.",
+	})
+
+	seedImportLastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "seed_import_last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful seed import.",
+	})
+
+	authValidationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_validations_total",
+		Help:      "Total number of credential validations by auth method, operation and outcome.",
+	}, []string{"method", "operation", "outcome"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		publishTotal,
+		dbOperationDuration,
+		seedImportsTotal,
+		seedImportServers,
+		seedImportDuration,
+		seedImportLastSuccess,
+		authValidationsTotal,
+	)
+}
+
+// Handler returns an http.Handler that serves the registry metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
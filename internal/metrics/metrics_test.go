@@ -0,0 +1,62 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/api/problem"
+	"github.com/modelcontextprotocol/registry/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrape returns the current metrics exposition
+func scrape(t *testing.T) string {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	body, err := io.ReadAll(rr.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMiddlewareLabelsByRoute(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v0/servers/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := metrics.Middleware()(mux)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v0/servers/abc", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/no/such/path", nil))
+
+	body := scrape(t)
+	assert.Contains(t, body, `mcp_registry_http_requests_total{method="GET",route="/v0/servers/{id}",status="418"} 1`)
+	assert.Contains(t, body, `mcp_registry_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `mcp_registry_http_request_duration_seconds_count{method="GET",route="/v0/servers/{id}",status="418"} 1`)
+	assert.NotContains(t, body, "/v0/servers/abc")
+}
+
+func TestInstrumentPublishOutcomes(t *testing.T) {
+	var fail bool
+	handler := metrics.InstrumentPublish(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			problem.Write(w, r, http.StatusTooManyRequests, problem.CodeRateLimited, "slow down")
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v0/publish", nil))
+	fail = true
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v0/publish", nil))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+
+	body := scrape(t)
+	assert.Contains(t, body, `mcp_registry_publish_total{outcome="success"} 1`)
+	assert.Contains(t, body, `mcp_registry_publish_total{outcome="rate_limited"} 1`)
+}
@@ -1,11 +1,24 @@
 package model
 
+import (
+	"regexp"
+	"time"
+)
+
 // AuthMethod represents the authentication method used
 type AuthMethod string
 
 const (
 	// AuthMethodGitHub represents GitHub OAuth authentication
 	AuthMethodGitHub AuthMethod = "github"
+	// AuthMethodGitHubOIDC represents a GitHub Actions OIDC token
+	AuthMethodGitHubOIDC AuthMethod = "github-oidc"
+	// AuthMethodGitLab represents GitLab OAuth or personal access token authentication
+	AuthMethodGitLab AuthMethod = "gitlab"
+	// AuthMethodDNS represents proof of domain control through a DNS TXT record
+	AuthMethodDNS AuthMethod = "dns"
+	// AuthMethodHTTP represents proof of domain control through a file served at a well-known URL
+	AuthMethodHTTP AuthMethod = "http"
 	// AuthMethodNone represents no authentication
 	AuthMethodNone AuthMethod = "none"
 )
@@ -60,6 +73,10 @@ type Input struct {
 	Properties  map[string]Input `json:"properties,omitempty" bson:"properties,omitempty"`
 }
 
+// TemplateVariable matches a {variable} reference in an input's value or template; the first
+// submatch is the variable's name
+var TemplateVariable = regexp.MustCompile(`\{([A-Za-z0-9_.-]+)\}`)
+
 type InputWithVariables struct {
 	Input     `json:",inline" bson:",inline"`
 	Variables map[string]Input `json:"variables,omitempty" bson:"variables,omitempty"`
@@ -97,9 +114,9 @@ type Package struct {
 
 // Remote represents a remote connection endpoint
 type Remote struct {
-	TransportType string  `json:"transport_type" bson:"transport_type"`
-	URL           string  `json:"url" bson:"url"`
-	Headers       []Input `json:"headers,omitempty" bson:"headers,omitempty"`
+	TransportType string          `json:"transport_type" bson:"transport_type"`
+	URL           string          `json:"url" bson:"url"`
+	Headers       []KeyValueInput `json:"headers,omitempty" bson:"headers,omitempty"`
 }
 
 // VersionDetail represents the version details of a server
@@ -123,4 +140,50 @@ type ServerDetail struct {
 	Server   `json:",inline" bson:",inline"`
 	Packages []Package `json:"packages,omitempty" bson:"packages,omitempty"`
 	Remotes  []Remote  `json:"remotes,omitempty" bson:"remotes,omitempty"`
+	// ChangeSeq is the registry-wide change sequence number of the last write to this entry.
+	// It is internal bookkeeping for exports and is not part of the API representation.
+	ChangeSeq int64 `json:"-" bson:"change_seq,omitempty"`
+}
+
+// AuditOperation identifies the kind of mutating operation recorded in the audit log
+type AuditOperation string
+
+const (
+	AuditOperationPublish    AuditOperation = "publish"
+	AuditOperationSeedImport AuditOperation = "seed_import"
+)
+
+// AuditRecord is an immutable entry in the audit log describing a single mutating operation
+type AuditRecord struct {
+	ID           string         `json:"id" bson:"id"`
+	Timestamp    time.Time      `json:"timestamp" bson:"timestamp"`
+	Actor        string         `json:"actor" bson:"actor"`
+	AuthMethod   AuthMethod     `json:"auth_method,omitempty" bson:"auth_method,omitempty"`
+	SourceIP     string         `json:"source_ip,omitempty" bson:"source_ip,omitempty"`
+	RequestID    string         `json:"request_id,omitempty" bson:"request_id,omitempty"`
+	Operation    AuditOperation `json:"operation" bson:"operation"`
+	Target       string         `json:"target" bson:"target"`
+	TargetID     string         `json:"target_id,omitempty" bson:"target_id,omitempty"`
+	BeforeDigest string         `json:"before_digest,omitempty" bson:"before_digest,omitempty"`
+	AfterDigest  string         `json:"after_digest,omitempty" bson:"after_digest,omitempty"`
+}
+
+// IdempotencyRecord stores the response to a request made with an Idempotency-Key header, so
+// that a retry of the request receives the original response instead of being executed again
+type IdempotencyRecord struct {
+	// Key is the client's key, scoped to the identity that sent it
+	Key string `json:"key" bson:"key"`
+	// RequestHash is a digest of the request, to detect the key being reused for another request
+	RequestHash string `json:"request_hash" bson:"request_hash"`
+	// StatusCode and Body hold the response; a zero StatusCode reserves the key for a request
+	// that is still in progress
+	StatusCode int       `json:"status_code" bson:"status_code"`
+	Body       []byte    `json:"body" bson:"body"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at" bson:"expires_at"`
+}
+
+// InProgress reports whether the record reserves its key for a request that hasn't finished
+func (r *IdempotencyRecord) InProgress() bool {
+	return r.StatusCode == 0
 }
@@ -0,0 +1,101 @@
+// Package pagination encodes list positions as opaque cursors. A cursor records the position, the
+// filters of the listing it was issued for and a format version, and is signed so that clients
+// can't forge positions or reuse a cursor with other filters.
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+)
+
+// version is the cursor format version; cursors of other versions are rejected
+const version = 1
+
+// ErrInvalidCursor is returned for a cursor that wasn't issued by the registry for the listing
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// payload is the signed content of a cursor
+type payload struct {
+	Version int    `json:"v"`
+	Key     string `json:"k,omitempty"`
+	ID      string `json:"i"`
+	Filters string `json:"f"`
+}
+
+// Codec signs and verifies cursors with a server secret
+type Codec struct {
+	secret []byte
+}
+
+// NewCodec creates a codec signing with secret. All instances of a deployment must share the
+// secret to accept each other's cursors.
+func NewCodec(secret []byte) *Codec {
+	return &Codec{secret: secret}
+}
+
+// Encode returns the cursor of the page after pos in a listing with the given filters, which
+// should be digested with FilterHash
+func (c *Codec) Encode(pos database.Position, filterHash string) string {
+	data, _ := json.Marshal(payload{Version: version, Key: pos.Key, ID: pos.ID, Filters: filterHash})
+	return base64.RawURLEncoding.EncodeToString(append(data, c.sign(data)...))
+}
+
+// Decode verifies cursor and returns its position. It fails with ErrInvalidCursor if the cursor
+// is malformed, was modified, or was issued for a listing with other filters.
+func (c *Codec) Decode(cursor, filterHash string) (database.Position, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(raw) <= sha256.Size {
+		return database.Position{}, fmt.Errorf("%w: malformed cursor", ErrInvalidCursor)
+	}
+
+	data, mac := raw[:len(raw)-sha256.Size], raw[len(raw)-sha256.Size:]
+	if !hmac.Equal(mac, c.sign(data)) {
+		return database.Position{}, fmt.Errorf("%w: signature mismatch", ErrInvalidCursor)
+	}
+
+	var p payload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return database.Position{}, fmt.Errorf("%w: malformed cursor", ErrInvalidCursor)
+	}
+	if p.Version != version {
+		return database.Position{}, fmt.Errorf("%w: unsupported cursor version %d", ErrInvalidCursor, p.Version)
+	}
+	if p.Filters != filterHash {
+		return database.Position{}, fmt.Errorf("%w: cursor was issued for a listing with different filters or sort order",
+			ErrInvalidCursor)
+	}
+	return database.Position{Key: p.Key, ID: p.ID}, nil
+}
+
+func (c *Codec) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// FilterHash digests the parameters that select and order the entries of a listing. Parameters
+// with empty values are ignored, so leaving one out and passing its default are equivalent.
+func FilterHash(filters map[string]string) string {
+	names := make([]string, 0, len(filters))
+	for name, value := range filters {
+		if value != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		// Length prefixes keep distinct filter sets from encoding to the same bytes
+		fmt.Fprintf(h, "%d:%s%d:%s", len(name), name, len(filters[name]), filters[name])
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
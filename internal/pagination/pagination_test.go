@@ -0,0 +1,58 @@
+package pagination_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodec(t *testing.T) {
+	codec := pagination.NewCodec([]byte("secret"))
+	filters := pagination.FilterHash(map[string]string{"sort": "name"})
+	pos := database.Position{Key: "io.github.example/server", ID: "550e8400-e29b-41d4-a716-446655440000"}
+
+	cursor := codec.Encode(pos, filters)
+	decoded, err := codec.Decode(cursor, filters)
+	require.NoError(t, err)
+	assert.Equal(t, pos, decoded)
+
+	t.Run("tampered", func(t *testing.T) {
+		raw, err := base64.RawURLEncoding.DecodeString(cursor)
+		require.NoError(t, err)
+		raw[10] ^= 1
+		_, err = codec.Decode(base64.RawURLEncoding.EncodeToString(raw), filters)
+		assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+		assert.ErrorContains(t, err, "signature mismatch")
+	})
+
+	t.Run("other secret", func(t *testing.T) {
+		_, err := pagination.NewCodec([]byte("other")).Decode(cursor, filters)
+		assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+	})
+
+	t.Run("other filters", func(t *testing.T) {
+		_, err := codec.Decode(cursor, pagination.FilterHash(map[string]string{"sort": "release_date"}))
+		assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+		assert.ErrorContains(t, err, "different filters")
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, cursor := range []string{"", "not base64!", "c2hvcnQ", pos.ID} {
+			_, err := codec.Decode(cursor, filters)
+			assert.ErrorIs(t, err, pagination.ErrInvalidCursor, cursor)
+		}
+	})
+}
+
+func TestFilterHash(t *testing.T) {
+	assert.Equal(t,
+		pagination.FilterHash(nil),
+		pagination.FilterHash(map[string]string{"sort": ""}))
+	assert.NotEqual(t,
+		pagination.FilterHash(map[string]string{"a": "bc"}),
+		pagination.FilterHash(map[string]string{"ab": "c"}))
+}
@@ -0,0 +1,247 @@
+// Package projection implements sparse fieldsets: selecting a subset of a server detail's fields
+// by their JSON paths, such as "name" or "packages.registry_name"
+package projection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/internal/model"
+)
+
+// ErrUnknownField is returned by Parse for a field path that server details don't have
+var ErrUnknownField = errors.New("unknown field")
+
+// Projection is a normalized set of JSON field paths. An empty projection selects every field.
+type Projection []string
+
+// Required lists the fields that databases return for every projection, since pagination
+// cursors and Last-Modified headers are derived from them
+var Required = []string{"id", "name", "version_detail.release_date"}
+
+// field describes a selectable field path
+type field struct {
+	// bsonPath is the path of the field in stored documents
+	bsonPath string
+}
+
+// fields maps every selectable JSON path of model.ServerDetail to its description
+var fields = map[string]field{}
+
+func init() {
+	collect(reflect.TypeOf(model.ServerDetail{}), "", "")
+}
+
+// collect records the paths of the fields of struct type t. Maps are leaves, since their keys
+// aren't known in advance.
+func collect(t reflect.Type, jsonPrefix, bsonPrefix string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		jsonName := tagName(f.Tag.Get("json"))
+		bsonName := tagName(f.Tag.Get("bson"))
+		if jsonName == "-" || !f.IsExported() {
+			continue
+		}
+		if f.Anonymous && jsonName == "" {
+			collect(f.Type, jsonPrefix, bsonPrefix)
+			continue
+		}
+		if jsonName == "" {
+			jsonName = f.Name
+		}
+		if bsonName == "" {
+			bsonName = strings.ToLower(f.Name)
+		}
+
+		jsonPath, bsonPath := jsonPrefix+jsonName, bsonPrefix+bsonName
+		fields[jsonPath] = field{bsonPath: bsonPath}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Slice {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			collect(ft, jsonPath+".", bsonPath+".")
+		}
+	}
+}
+
+// tagName returns the name part of a struct tag value
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
+
+// Parse parses a comma-separated list of field paths. Every path must exist; fields already
+// covered by a selected parent are dropped, so "packages,packages.name" selects "packages".
+func Parse(spec string) (Projection, error) {
+	var (
+		selected []string
+		unknown  []string
+	)
+	for _, path := range strings.Split(spec, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if _, ok := fields[path]; !ok {
+			unknown = append(unknown, path)
+			continue
+		}
+		selected = append(selected, path)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownField, strings.Join(unknown, ", "))
+	}
+	return normalize(selected), nil
+}
+
+// normalize sorts paths and removes duplicates and paths below another selected path
+func normalize(paths []string) Projection {
+	sort.Strings(paths)
+	var result Projection
+	for _, path := range paths {
+		covered := false
+		for _, parent := range result {
+			if path == parent || strings.HasPrefix(path, parent+".") {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			result = append(result, path)
+		}
+	}
+	return result
+}
+
+// With returns the projection extended with paths, e.g. fields a query needs internally.
+// An empty projection stays empty, since it already selects everything.
+func (p Projection) With(paths ...string) Projection {
+	if len(p) == 0 {
+		return p
+	}
+	return normalize(append(append([]string{}, p...), paths...))
+}
+
+// BSONPaths returns the paths of the selected fields in stored documents
+func (p Projection) BSONPaths() []string {
+	paths := make([]string, len(p))
+	for i, path := range p {
+		paths[i] = fields[path].bsonPath
+	}
+	return paths
+}
+
+// tree is a projection as nested selections; a nil subtree selects the whole field
+type tree map[string]tree
+
+func (p Projection) tree() tree {
+	root := tree{}
+	for _, path := range p {
+		node := root
+		parts := strings.Split(path, ".")
+		for i, part := range parts {
+			if i == len(parts)-1 {
+				node[part] = nil
+				break
+			}
+			child, ok := node[part]
+			if !ok {
+				child = tree{}
+				node[part] = child
+			}
+			node = child
+		}
+	}
+	return root
+}
+
+// Mask returns a copy of detail with only the selected fields set. Other fields are left at
+// their zero value; the original is not modified.
+func (p Projection) Mask(detail *model.ServerDetail) *model.ServerDetail {
+	if len(p) == 0 {
+		masked := *detail
+		return &masked
+	}
+	var masked model.ServerDetail
+	maskStruct(reflect.ValueOf(&masked).Elem(), reflect.ValueOf(detail).Elem(), p.tree())
+	return &masked
+}
+
+// maskStruct copies the fields of src selected by t into dst
+func maskStruct(dst, src reflect.Value, t tree) {
+	typ := src.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name := tagName(f.Tag.Get("json"))
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			maskStruct(dst.Field(i), src.Field(i), t)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		sub, selected := t[name]
+		switch {
+		case !selected:
+		case sub == nil:
+			dst.Field(i).Set(src.Field(i))
+		case f.Type.Kind() == reflect.Struct:
+			maskStruct(dst.Field(i), src.Field(i), sub)
+		case f.Type.Kind() == reflect.Slice && !src.Field(i).IsNil():
+			// A new slice, so masking never writes into the original's elements
+			elems := reflect.MakeSlice(f.Type, src.Field(i).Len(), src.Field(i).Len())
+			for j := 0; j < elems.Len(); j++ {
+				maskStruct(elems.Index(j), src.Field(i).Index(j), sub)
+			}
+			dst.Field(i).Set(elems)
+		}
+	}
+}
+
+// Render converts v to its JSON object form containing only the selected fields, so that
+// unselected fields are left out rather than rendered with zero values
+func (p Projection) Render(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(p) == 0 {
+		return doc, nil
+	}
+	return prune(doc, p.tree()), nil
+}
+
+// prune removes the members of doc not selected by t, descending into arrays element-wise
+func prune(doc any, t tree) any {
+	switch v := doc.(type) {
+	case map[string]any:
+		for key, value := range v {
+			sub, selected := t[key]
+			switch {
+			case !selected:
+				delete(v, key)
+			case sub != nil:
+				v[key] = prune(value, sub)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = prune(v[i], t)
+		}
+	}
+	return doc
+}
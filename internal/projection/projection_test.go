@@ -0,0 +1,75 @@
+package projection_test
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/projection"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	p, err := projection.Parse(" version_detail.version,name,packages, packages.registry_name,name,")
+	require.NoError(t, err)
+	assert.Equal(t, projection.Projection{"name", "packages", "version_detail.version"}, p)
+
+	p, err = projection.Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, p)
+
+	// Fields of inlined structs are addressed without the struct's own name
+	p, err = projection.Parse("packages.environment_variables.is_secret,remotes.headers.format")
+	require.NoError(t, err)
+	assert.Len(t, p, 2)
+
+	_, err = projection.Parse("name,nmae,change_seq,packages.registry")
+	require.ErrorIs(t, err, projection.ErrUnknownField)
+	assert.Contains(t, err.Error(), "nmae, change_seq, packages.registry")
+}
+
+func TestProjection(t *testing.T) {
+	detail := &model.ServerDetail{
+		Server: model.Server{
+			ID:            "a5e8a7f0-d4e4-4a1d-b12f-2896a23fd4f1",
+			Name:          "io.github.example/weather",
+			Description:   "Weather forecasts",
+			VersionDetail: model.VersionDetail{Version: "1.2.0", ReleaseDate: "2025-05-27T12:00:00Z"},
+		},
+		Packages: []model.Package{
+			{RegistryName: "npm", Name: "@example/weather", Version: "1.2.0"},
+			{RegistryName: "docker", Name: "example/weather", Version: "1.2.0"},
+		},
+	}
+	p, err := projection.Parse("name,version_detail.version,packages.registry_name")
+	require.NoError(t, err)
+
+	t.Run("paths in stored documents", func(t *testing.T) {
+		assert.Equal(t, []string{"name", "packages.registry_name", "version_detail.version"}, p.BSONPaths())
+	})
+
+	t.Run("mask copies selected fields only", func(t *testing.T) {
+		masked := p.With(projection.Required...).Mask(detail)
+		assert.Equal(t, detail.ID, masked.ID)
+		assert.Equal(t, detail.Name, masked.Name)
+		assert.Empty(t, masked.Description)
+		assert.Equal(t, model.VersionDetail{Version: "1.2.0", ReleaseDate: "2025-05-27T12:00:00Z"}, masked.VersionDetail)
+		assert.Equal(t, []model.Package{{RegistryName: "npm"}, {RegistryName: "docker"}}, masked.Packages)
+
+		masked.Packages[0].RegistryName = "pypi"
+		assert.Equal(t, "npm", detail.Packages[0].RegistryName)
+	})
+
+	t.Run("render leaves out unselected fields", func(t *testing.T) {
+		rendered, err := p.Render(detail)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"name":           "io.github.example/weather",
+			"version_detail": map[string]any{"version": "1.2.0"},
+			"packages": []any{
+				map[string]any{"registry_name": "npm"},
+				map[string]any{"registry_name": "docker"},
+			},
+		}, rendered)
+	})
+}
@@ -0,0 +1,180 @@
+// Package ratelimit provides per-identity and per-namespace limits on publishing, and
+// per-source-IP limits on starting authentication flows
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+)
+
+// ErrRateLimited is returned when a request exceeds one of the configured limits
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// ExceededError describes which limit was exceeded and when the caller may retry
+type ExceededError struct {
+	// Action is what the limit counts, "publishes" or "auth flows"
+	Action string
+	// Scope is the kind of limit that was exceeded, "identity", "namespace" or "source IP"
+	Scope string
+	// Key is the identity, namespace or IP address the limit applies to
+	Key string
+	// Limit is the maximum number of actions allowed in the window
+	Limit int
+	// Window is the length of the limit window
+	Window time.Duration
+	// RetryAfter is how long the caller should wait before trying again
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s: at most %d %s per %s allowed for %s %s",
+		ErrRateLimited, e.Limit, e.Action, e.Window, e.Scope, e.Key)
+}
+
+// Unwrap allows errors.Is(err, ErrRateLimited)
+func (e *ExceededError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// Limiter enforces publish limits keyed by authenticated identity and by namespace, and auth
+// flow limits keyed by source IP
+type Limiter struct {
+	store           Store
+	adminIdentities []string
+	identityLimit   int
+	identityWindow  time.Duration
+	namespaceLimit  int
+	namespaceWindow time.Duration
+	authFlowLimit   int
+	authFlowWindow  time.Duration
+	now             func() time.Time
+}
+
+// NewLimiter creates a new publish limiter using the limits from the configuration.
+// A limit of zero or less disables the corresponding check.
+func NewLimiter(cfg *config.Config, store Store) *Limiter {
+	return &Limiter{
+		store:           store,
+		adminIdentities: cfg.AdminIdentities,
+		identityLimit:   cfg.PublishRateLimitPerHour,
+		identityWindow:  time.Hour,
+		namespaceLimit:  cfg.PublishVersionsPerDay,
+		namespaceWindow: 24 * time.Hour,
+		authFlowLimit:   cfg.AuthFlowRateLimitPerHour,
+		authFlowWindow:  time.Hour,
+		now:             time.Now,
+	}
+}
+
+// Reservation is a slot of a namespace's publish limit, taken by AllowPublish for a publish
+// that is in progress. The zero Reservation holds no slot.
+type Reservation struct {
+	store   Store
+	key     string
+	window  time.Duration
+	resetAt time.Time
+}
+
+// Release gives the slot back, for a publish that failed, so that rejected publishes don't use
+// up the namespace's versions.
+func (r Reservation) Release(ctx context.Context) error {
+	if r.store == nil {
+		return nil
+	}
+	if err := r.store.Decrement(ctx, r.key, r.window, r.resetAt); err != nil {
+		return fmt.Errorf("failed to release rate limit reservation: %w", err)
+	}
+	return nil
+}
+
+// AllowPublish records a publish attempt by identity for the server name and returns an
+// *ExceededError if either the identity or the namespace limit has been exceeded. Attempts count
+// against the identity limit. The namespace limit is checked by reserving a slot up front, so
+// that concurrent publishes can't exceed it; the caller must release the returned reservation if
+// the publish fails. Admin identities are exempt from all limits.
+func (l *Limiter) AllowPublish(ctx context.Context, identity *auth.Identity, serverName string) (Reservation, error) {
+	if auth.IsAdmin(l.adminIdentities, identity) {
+		return Reservation{}, nil
+	}
+
+	if l.identityLimit > 0 && identity != nil {
+		key := "publish:identity:" + identity.String()
+		count, resetAt, err := l.store.Increment(ctx, key, l.identityWindow)
+		if err != nil {
+			return Reservation{}, fmt.Errorf("failed to check rate limit: %w", err)
+		}
+		if count > l.identityLimit {
+			return Reservation{}, l.exceeded("publishes", "identity", identity.String(), l.identityLimit, l.identityWindow, resetAt)
+		}
+	}
+
+	if l.namespaceLimit <= 0 {
+		return Reservation{}, nil
+	}
+
+	namespace := Namespace(serverName)
+	key := "publish:namespace:" + namespace
+	count, resetAt, err := l.store.Increment(ctx, key, l.namespaceWindow)
+	if err != nil {
+		return Reservation{}, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	reservation := Reservation{store: l.store, key: key, window: l.namespaceWindow, resetAt: resetAt}
+	if count > l.namespaceLimit {
+		// Over the limit, the slot isn't taken after all
+		if err := reservation.Release(ctx); err != nil {
+			return Reservation{}, err
+		}
+		return Reservation{}, l.exceeded("publishes", "namespace", namespace, l.namespaceLimit, l.namespaceWindow, resetAt)
+	}
+	return reservation, nil
+}
+
+// AllowAuthFlow records an attempt to start an authentication flow from sourceIP and returns an
+// *ExceededError if the source IP has started too many. Starting a flow is unauthenticated, so
+// this keeps clients from exhausting the flow stores or the registry's GitHub OAuth app.
+func (l *Limiter) AllowAuthFlow(ctx context.Context, sourceIP string) error {
+	if l.authFlowLimit <= 0 {
+		return nil
+	}
+
+	count, resetAt, err := l.store.Increment(ctx, "auth_flow:ip:"+sourceIP, l.authFlowWindow)
+	if err != nil {
+		return fmt.Errorf("failed to check rate limit: %w", err)
+	}
+	if count > l.authFlowLimit {
+		return l.exceeded("auth flows", "source IP", sourceIP, l.authFlowLimit, l.authFlowWindow, resetAt)
+	}
+	return nil
+}
+
+// exceeded builds the error for a limit whose window resets at resetAt
+func (l *Limiter) exceeded(
+	action, scope, key string, limit int, window time.Duration, resetAt time.Time,
+) error {
+	retryAfter := resetAt.Sub(l.now())
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+
+	return &ExceededError{
+		Action:     action,
+		Scope:      scope,
+		Key:        key,
+		Limit:      limit,
+		Window:     window,
+		RetryAfter: retryAfter,
+	}
+}
+
+// Namespace returns the namespace part of a server name, i.e. everything before the first "/".
+// For io.github.example/my-server this is io.github.example.
+func Namespace(serverName string) string {
+	namespace, _, _ := strings.Cut(serverName, "/")
+	return namespace
+}
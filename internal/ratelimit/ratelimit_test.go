@@ -0,0 +1,132 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterAllowPublish(t *testing.T) {
+	alice := &auth.Identity{Method: model.AuthMethodGitHub, Subject: "alice"}
+	bob := &auth.Identity{Method: model.AuthMethodGitHub, Subject: "bob"}
+	admin := &auth.Identity{Method: model.AuthMethodGitHub, Subject: "admin"}
+
+	stores := map[string]ratelimit.Store{
+		"memory":   ratelimit.NewMemoryStore(),
+		"database": ratelimit.NewDatabaseStore(database.NewMemoryDB(map[string]*model.Server{})),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			limiter := ratelimit.NewLimiter(&config.Config{
+				AdminIdentities:         []string{"github:admin"},
+				PublishRateLimitPerHour: 2,
+				PublishVersionsPerDay:   3,
+			}, store)
+
+			// Identity limit
+			_, err := limiter.AllowPublish(ctx, alice, "io.github.alice/one")
+			require.NoError(t, err)
+			_, err = limiter.AllowPublish(ctx, alice, "io.github.alice/two")
+			require.NoError(t, err)
+
+			_, err = limiter.AllowPublish(ctx, alice, "io.github.alice/three")
+			var exceeded *ratelimit.ExceededError
+			require.True(t, errors.As(err, &exceeded))
+			assert.True(t, errors.Is(err, ratelimit.ErrRateLimited))
+			assert.Equal(t, "identity", exceeded.Scope)
+			assert.Equal(t, "github:alice", exceeded.Key)
+			assert.Positive(t, exceeded.RetryAfter)
+
+			// Namespace limit applies across identities, and only to successful publishes
+			publish := func(identity *auth.Identity, serverName string) error {
+				_, err := limiter.AllowPublish(ctx, identity, serverName)
+				return err
+			}
+			require.NoError(t, publish(bob, "io.github.shared/server"))
+			require.NoError(t, publish(bob, "io.github.shared/server"))
+
+			// Rejected publishes don't use up the namespace's versions
+			carol := &auth.Identity{Method: model.AuthMethodGitHub, Subject: "carol"}
+			reservation, err := limiter.AllowPublish(ctx, carol, "io.github.shared/other")
+			require.NoError(t, err)
+			require.NoError(t, reservation.Release(ctx))
+			require.NoError(t, publish(carol, "io.github.shared/other"))
+
+			err = publish(&auth.Identity{Method: model.AuthMethodGitHub, Subject: "dave"}, "io.github.shared/other")
+			require.True(t, errors.As(err, &exceeded))
+			assert.Equal(t, "namespace", exceeded.Scope)
+			assert.Equal(t, "io.github.shared", exceeded.Key)
+			assert.Positive(t, exceeded.RetryAfter)
+
+			// Admins are exempt
+			for range 5 {
+				require.NoError(t, publish(admin, "io.github.shared/server"))
+			}
+		})
+	}
+}
+
+func TestLimiterAllowPublishConcurrent(t *testing.T) {
+	ctx := context.Background()
+	limiter := ratelimit.NewLimiter(&config.Config{PublishVersionsPerDay: 3}, ratelimit.NewMemoryStore())
+
+	// Concurrent publishes to one namespace can't all pass the check before any is counted
+	var allowed []ratelimit.Reservation
+	var exceeded int
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			identity := &auth.Identity{Method: model.AuthMethodGitHub, Subject: fmt.Sprintf("user-%d", i)}
+			reservation, err := limiter.AllowPublish(ctx, identity, "io.github.shared/server")
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.Is(err, ratelimit.ErrRateLimited) {
+				exceeded++
+			} else if assert.NoError(t, err) {
+				allowed = append(allowed, reservation)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, allowed, 3)
+	assert.Equal(t, 17, exceeded)
+
+	// Attempts over the limit don't hold on to slots, so a released slot can be taken again
+	require.NoError(t, allowed[0].Release(ctx))
+	_, err := limiter.AllowPublish(ctx, nil, "io.github.shared/server")
+	require.NoError(t, err)
+	_, err = limiter.AllowPublish(ctx, nil, "io.github.shared/server")
+	assert.ErrorIs(t, err, ratelimit.ErrRateLimited)
+}
+
+func TestLimiterAllowAuthFlow(t *testing.T) {
+	ctx := context.Background()
+	limiter := ratelimit.NewLimiter(&config.Config{AuthFlowRateLimitPerHour: 2}, ratelimit.NewMemoryStore())
+
+	require.NoError(t, limiter.AllowAuthFlow(ctx, "203.0.113.7"))
+	require.NoError(t, limiter.AllowAuthFlow(ctx, "203.0.113.7"))
+
+	err := limiter.AllowAuthFlow(ctx, "203.0.113.7")
+	var exceeded *ratelimit.ExceededError
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, "source IP", exceeded.Scope)
+	assert.Equal(t, "rate limit exceeded: at most 2 auth flows per 1h0m0s allowed for source IP 203.0.113.7", err.Error())
+
+	// Other sources have their own limit
+	require.NoError(t, limiter.AllowAuthFlow(ctx, "198.51.100.1"))
+}
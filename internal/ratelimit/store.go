@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+)
+
+// Store persists fixed-window counters used by the Limiter
+type Store interface {
+	// Increment increments the counter for key in the current window of the given length
+	// and returns the new count and the time at which the window resets
+	Increment(ctx context.Context, key string, window time.Duration) (int, time.Time, error)
+	// Decrement undoes an Increment of key that returned resetAt, unless its window has ended
+	Decrement(ctx context.Context, key string, window time.Duration, resetAt time.Time) error
+}
+
+// NewMemoryStore creates a counter store that keeps counters in memory, suitable for
+// single-node deployments. It uses the counters of an in-memory database of its own.
+func NewMemoryStore() *DatabaseStore {
+	return NewDatabaseStore(database.NewMemoryDB(nil))
+}
+
+// DatabaseStore is a Store backed by the registry database, so that limits are shared
+// between replicas
+type DatabaseStore struct {
+	db database.Database
+}
+
+// NewDatabaseStore creates a counter store that persists counters in the database
+func NewDatabaseStore(db database.Database) *DatabaseStore {
+	return &DatabaseStore{db: db}
+}
+
+// Increment increments the counter for key in the current window
+func (s *DatabaseStore) Increment(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	return s.db.IncrementCounter(ctx, key, window)
+}
+
+// Decrement undoes an increment of key in the window that resets at resetAt
+func (s *DatabaseStore) Decrement(ctx context.Context, key string, window time.Duration, resetAt time.Time) error {
+	return s.db.DecrementCounter(ctx, key, window, resetAt)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/projection"
 )
 
 // fakeRegistryService implements RegistryService interface with an in-memory database
@@ -78,30 +79,21 @@ func NewFakeRegistryService() RegistryService {
 	}
 }
 
-// List retrieves MCPRegistry entries with optional filtering and pagination
-func (s *fakeRegistryService) List(cursor string, limit int) ([]model.Server, string, error) {
+// ListWithOptions retrieves entries in the order, page and shape given by opts
+func (s *fakeRegistryService) ListWithOptions(
+	ctx context.Context, opts database.ListOptions,
+) (*database.ListResult, error) {
 	// Create a timeout context for the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	// Use the database's List method with no filters to get all entries
-	entries, nextCursor, err := s.db.List(ctx, nil, cursor, limit)
-	if err != nil {
-		return nil, "", err
-	}
-	// Convert from []*model.Server to []model.Server
-	result := make([]model.Server, len(entries))
-	for i, entry := range entries {
-		result[i] = *entry
-	}
-
-	return result, nextCursor, nil
+	return s.db.ListWithOptions(ctx, nil, opts)
 }
 
 // GetByID retrieves a specific server detail by its ID
-func (s *fakeRegistryService) GetByID(id string) (*model.ServerDetail, error) {
+func (s *fakeRegistryService) GetByID(ctx context.Context, id string) (*model.ServerDetail, error) {
 	// Create a timeout context for the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	// Use the database's GetByID method to retrieve the server detail
@@ -113,14 +105,62 @@ func (s *fakeRegistryService) GetByID(id string) (*model.ServerDetail, error) {
 	return serverDetail, nil
 }
 
+// GetByIDProjected retrieves a specific server detail by its ID, with only the fields selected by p set
+func (s *fakeRegistryService) GetByIDProjected(
+	ctx context.Context, id string, p projection.Projection,
+) (*model.ServerDetail, error) {
+	// Create a timeout context for the database operation
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.db.GetByIDProjected(ctx, id, p)
+}
+
+// BatchGet retrieves the server details with the given IDs and name@version references
+func (s *fakeRegistryService) BatchGet(
+	ctx context.Context, ids []string, refs []database.ServerRef,
+) ([]model.ServerDetail, []string, error) {
+	// Create a timeout context for the database operation
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return batchGet(ctx, s.db, ids, refs)
+}
+
 // Publish adds a new server detail to the in-memory database
-func (s *fakeRegistryService) Publish(serverDetail *model.ServerDetail) error {
+func (s *fakeRegistryService) Publish(ctx context.Context, serverDetail *model.ServerDetail) error {
 	// Create a timeout context for the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	// Use the database's Publish method to add the server detail
-	return s.db.Publish(ctx, serverDetail)
+	return s.db.Publish(ctx, serverDetail, publishAudit(ctx, serverDetail))
+}
+
+// ListAuditRecords returns audit log records from the in-memory database
+func (s *fakeRegistryService) ListAuditRecords(
+	ctx context.Context, filter database.AuditFilter, cursor string, limit int,
+) ([]model.AuditRecord, string, error) {
+	// Create a timeout context for the database operation
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	records, nextCursor, err := s.db.ListAuditRecords(ctx, filter, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result := make([]model.AuditRecord, len(records))
+	for i, record := range records {
+		result[i] = *record
+	}
+
+	return result, nextCursor, nil
+}
+
+// Export opens a snapshot of the in-memory database
+func (s *fakeRegistryService) Export(ctx context.Context) (database.ServerIterator, error) {
+	return s.db.Export(ctx)
 }
 
 // Close closes the in-memory database connection
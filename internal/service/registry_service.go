@@ -4,8 +4,12 @@ import (
 	"context"
 	"time"
 
+	"github.com/modelcontextprotocol/registry/internal/audit"
 	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/projection"
+	"github.com/modelcontextprotocol/registry/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // registryServiceImpl implements the RegistryService interface using our Database
@@ -22,82 +26,181 @@ func NewRegistryServiceWithDB(db database.Database) RegistryService {
 	}
 }
 
-// GetAll returns all registry entries
-func (s *registryServiceImpl) GetAll() ([]model.Server, error) {
+// ListWithOptions returns registry entries in the order, page and shape given by opts
+func (s *registryServiceImpl) ListWithOptions(
+	ctx context.Context, opts database.ListOptions,
+) (_ *database.ListResult, err error) {
+	ctx, span := tracing.Start(ctx, "RegistryService.ListWithOptions",
+		attribute.Int("limit", opts.Limit),
+		attribute.String("sort", string(opts.Sort)),
+		attribute.StringSlice("fields", opts.Fields),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	// Create a timeout context for the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	// Use the database's List method with no filters to get all entries
-	entries, _, err := s.db.List(ctx, nil, "", 30)
-	if err != nil {
-		return nil, err
-	}
-
-	// Convert from []*model.Server to []model.Server
-	result := make([]model.Server, len(entries))
-	for i, entry := range entries {
-		result[i] = *entry
+	// If limit is not set or negative, use a default limit
+	if opts.Limit <= 0 {
+		opts.Limit = 30
 	}
 
-	return result, nil
+	return s.db.ListWithOptions(ctx, nil, opts)
 }
 
-// List returns registry entries with cursor-based pagination
-func (s *registryServiceImpl) List(cursor string, limit int) ([]model.Server, string, error) {
+// GetByID retrieves a specific server detail by its ID
+func (s *registryServiceImpl) GetByID(ctx context.Context, id string) (_ *model.ServerDetail, err error) {
+	ctx, span := tracing.Start(ctx, "RegistryService.GetByID", attribute.String("server.id", id))
+	defer func() { tracing.End(span, err) }()
+
 	// Create a timeout context for the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	// If limit is not set or negative, use a default limit
-	if limit <= 0 {
-		limit = 30
-	}
-
-	// Use the database's List method with pagination
-	entries, nextCursor, err := s.db.List(ctx, nil, cursor, limit)
+	// Use the database's GetByID method to retrieve the server detail
+	serverDetail, err := s.db.GetByID(ctx, id)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	// Convert from []*model.Server to []model.Server
-	result := make([]model.Server, len(entries))
-	for i, entry := range entries {
-		result[i] = *entry
-	}
+	return serverDetail, nil
+}
 
-	return result, nextCursor, nil
+// GetByIDProjected retrieves a specific server detail by its ID, with only the fields selected by p set
+func (s *registryServiceImpl) GetByIDProjected(
+	ctx context.Context, id string, p projection.Projection,
+) (_ *model.ServerDetail, err error) {
+	ctx, span := tracing.Start(ctx, "RegistryService.GetByIDProjected",
+		attribute.String("server.id", id), attribute.StringSlice("fields", p))
+	defer func() { tracing.End(span, err) }()
+
+	// Create a timeout context for the database operation
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.db.GetByIDProjected(ctx, id, p)
 }
 
-// GetByID retrieves a specific server detail by its ID
-func (s *registryServiceImpl) GetByID(id string) (*model.ServerDetail, error) {
+// BatchGet retrieves the server details with the given IDs and name@version references using a
+// single database query. Results follow the order of the request, IDs first, and every requested
+// key that matched nothing is returned in missing.
+func (s *registryServiceImpl) BatchGet(
+	ctx context.Context, ids []string, refs []database.ServerRef,
+) (_ []model.ServerDetail, _ []string, err error) {
+	ctx, span := tracing.Start(ctx, "RegistryService.BatchGet",
+		attribute.Int("ids", len(ids)), attribute.Int("refs", len(refs)))
+	defer func() { tracing.End(span, err) }()
+
 	// Create a timeout context for the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	// Use the database's GetByID method to retrieve the server detail
-	serverDetail, err := s.db.GetByID(ctx, id)
+	return batchGet(ctx, s.db, ids, refs)
+}
+
+// batchGet runs a batch lookup and matches the results back to the requested keys
+func batchGet(
+	ctx context.Context, db database.Database, ids []string, refs []database.ServerRef,
+) ([]model.ServerDetail, []string, error) {
+	entries, err := db.BatchGet(ctx, ids, refs)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return serverDetail, nil
+	byID := make(map[string]*model.ServerDetail, len(entries))
+	byRef := make(map[database.ServerRef]*model.ServerDetail, len(entries))
+	for _, entry := range entries {
+		byID[entry.ID] = entry
+		byRef[database.ServerRef{Name: entry.Name, Version: entry.VersionDetail.Version}] = entry
+	}
+
+	var (
+		found   []model.ServerDetail
+		missing []string
+		seen    = map[string]bool{}
+	)
+	add := func(key string, entry *model.ServerDetail) {
+		if entry == nil {
+			if !seen[key] {
+				missing = append(missing, key)
+			}
+			seen[key] = true
+			return
+		}
+		if !seen[entry.ID] {
+			found = append(found, *entry)
+		}
+		seen[key] = true
+		seen[entry.ID] = true
+	}
+	for _, id := range ids {
+		add(id, byID[id])
+	}
+	for _, ref := range refs {
+		add(ref.String(), byRef[ref])
+	}
+	return found, missing, nil
 }
 
 // Publish adds a new server detail to the registry
-func (s *registryServiceImpl) Publish(serverDetail *model.ServerDetail) error {
+func (s *registryServiceImpl) Publish(ctx context.Context, serverDetail *model.ServerDetail) (err error) {
+	ctx, span := tracing.Start(ctx, "RegistryService.Publish")
+	defer func() { tracing.End(span, err) }()
+
 	// Create a timeout context for the database operation
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	if serverDetail == nil {
 		return database.ErrInvalidInput
 	}
+	span.SetAttributes(
+		attribute.String("server.name", serverDetail.Name),
+		attribute.String("server.version", serverDetail.VersionDetail.Version),
+	)
+
+	return s.db.Publish(ctx, serverDetail, publishAudit(ctx, serverDetail))
+}
+
+// ListAuditRecords returns audit log records, newest first, with cursor-based pagination
+func (s *registryServiceImpl) ListAuditRecords(
+	ctx context.Context, filter database.AuditFilter, cursor string, limit int,
+) (_ []model.AuditRecord, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "RegistryService.ListAuditRecords", attribute.Int("limit", limit))
+	defer func() { tracing.End(span, err) }()
 
-	err := s.db.Publish(ctx, serverDetail)
+	// Create a timeout context for the database operation
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	records, nextCursor, err := s.db.ListAuditRecords(ctx, filter, cursor, limit)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
-	return nil
+	// Convert from []*model.AuditRecord to []model.AuditRecord
+	result := make([]model.AuditRecord, len(records))
+	for i, record := range records {
+		result[i] = *record
+	}
+
+	return result, nextCursor, nil
+}
+
+// Export opens a point-in-time snapshot of every server detail for streaming.
+// No timeout is applied because the snapshot lives as long as the caller streams it.
+func (s *registryServiceImpl) Export(ctx context.Context) (_ database.ServerIterator, err error) {
+	ctx, span := tracing.Start(ctx, "RegistryService.Export")
+	defer func() { tracing.End(span, err) }()
+
+	return s.db.Export(ctx)
+}
+
+// publishAudit returns the function building the audit record of a publish of serverDetail by
+// the actor in ctx
+func publishAudit(ctx context.Context, serverDetail *model.ServerDetail) database.AuditFunc {
+	return func(previous *model.ServerDetail) *model.AuditRecord {
+		return audit.PublishRecord(ctx, previous, serverDetail)
+	}
 }
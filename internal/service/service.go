@@ -1,10 +1,20 @@
 package service
 
-import "github.com/modelcontextprotocol/registry/internal/model"
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/projection"
+)
 
 // RegistryService defines the interface for registry operations
 type RegistryService interface {
-	List(cursor string, limit int) ([]model.Server, string, error)
-	GetByID(id string) (*model.ServerDetail, error)
-	Publish(serverDetail *model.ServerDetail) error
+	ListWithOptions(ctx context.Context, opts database.ListOptions) (*database.ListResult, error)
+	GetByID(ctx context.Context, id string) (*model.ServerDetail, error)
+	GetByIDProjected(ctx context.Context, id string, p projection.Projection) (*model.ServerDetail, error)
+	BatchGet(ctx context.Context, ids []string, refs []database.ServerRef) ([]model.ServerDetail, []string, error)
+	Publish(ctx context.Context, serverDetail *model.ServerDetail) error
+	ListAuditRecords(ctx context.Context, filter database.AuditFilter, cursor string, limit int) ([]model.AuditRecord, string, error)
+	Export(ctx context.Context) (database.ServerIterator, error)
 }
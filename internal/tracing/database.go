@@ -0,0 +1,182 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/projection"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedDatabase creates a span around every operation of the wrapped database
+type tracedDatabase struct {
+	db      database.Database
+	backend string
+}
+
+// InstrumentDatabase wraps db so that each operation runs in its own span, named after the
+// method called. Backends that issue several queries per operation can add child spans below it.
+func InstrumentDatabase(db database.Database, backend string) database.Database {
+	return &tracedDatabase{db: db, backend: backend}
+}
+
+// start creates the span for operation
+func (d *tracedDatabase) start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("db.backend", d.backend))
+	return Start(ctx, "db."+operation, attrs...)
+}
+
+// end ends the span, not treating expected misses as span errors
+func (d *tracedDatabase) end(span trace.Span, err error) {
+	if errors.Is(err, database.ErrNotFound) {
+		span.SetAttributes(attribute.Bool("db.not_found", true))
+		err = nil
+	}
+	End(span, err)
+}
+
+func (d *tracedDatabase) ListWithOptions(
+	ctx context.Context, filter map[string]interface{}, opts database.ListOptions,
+) (*database.ListResult, error) {
+	ctx, span := d.start(ctx, "ListWithOptions",
+		attribute.Int("db.limit", opts.Limit),
+		attribute.String("db.sort", string(opts.Sort)),
+		attribute.StringSlice("db.fields", opts.Fields),
+	)
+	result, err := d.db.ListWithOptions(ctx, filter, opts)
+	d.end(span, err)
+	return result, err
+}
+
+func (d *tracedDatabase) GetByID(ctx context.Context, id string) (*model.ServerDetail, error) {
+	ctx, span := d.start(ctx, "GetByID", attribute.String("server.id", id))
+	detail, err := d.db.GetByID(ctx, id)
+	d.end(span, err)
+	return detail, err
+}
+
+func (d *tracedDatabase) GetByIDProjected(
+	ctx context.Context, id string, p projection.Projection,
+) (*model.ServerDetail, error) {
+	ctx, span := d.start(ctx, "GetByIDProjected", attribute.String("server.id", id), attribute.StringSlice("db.fields", p))
+	detail, err := d.db.GetByIDProjected(ctx, id, p)
+	d.end(span, err)
+	return detail, err
+}
+
+func (d *tracedDatabase) BatchGet(
+	ctx context.Context, ids []string, refs []database.ServerRef,
+) ([]*model.ServerDetail, error) {
+	ctx, span := d.start(ctx, "BatchGet", attribute.Int("db.ids", len(ids)), attribute.Int("db.refs", len(refs)))
+	details, err := d.db.BatchGet(ctx, ids, refs)
+	d.end(span, err)
+	return details, err
+}
+
+func (d *tracedDatabase) Publish(
+	ctx context.Context, serverDetail *model.ServerDetail, auditFn database.AuditFunc,
+) error {
+	var attrs []attribute.KeyValue
+	if serverDetail != nil {
+		attrs = append(attrs, attribute.String("server.name", serverDetail.Name))
+	}
+	ctx, span := d.start(ctx, "Publish", attrs...)
+	err := d.db.Publish(ctx, serverDetail, auditFn)
+	d.end(span, err)
+	return err
+}
+
+func (d *tracedDatabase) ImportSeed(
+	ctx context.Context, seedFilePath string, auditFn database.SeedAuditFunc,
+) (database.ImportStats, error) {
+	ctx, span := d.start(ctx, "ImportSeed", attribute.String("seed.path", seedFilePath))
+	stats, err := d.db.ImportSeed(ctx, seedFilePath, auditFn)
+	span.SetAttributes(
+		attribute.Int("seed.total", stats.Total),
+		attribute.Int("seed.created", stats.Created),
+		attribute.Int("seed.updated", stats.Updated),
+		attribute.Int("seed.failed", stats.Failed),
+	)
+	d.end(span, err)
+	return stats, err
+}
+
+func (d *tracedDatabase) Export(ctx context.Context) (database.ServerIterator, error) {
+	ctx, span := d.start(ctx, "Export")
+	it, err := d.db.Export(ctx)
+	if err == nil {
+		span.SetAttributes(attribute.Int64("db.change_seq", it.ChangeSeq()))
+	}
+	d.end(span, err)
+	return it, err
+}
+
+func (d *tracedDatabase) InsertAuditRecord(ctx context.Context, record *model.AuditRecord) error {
+	ctx, span := d.start(ctx, "InsertAuditRecord")
+	err := d.db.InsertAuditRecord(ctx, record)
+	d.end(span, err)
+	return err
+}
+
+func (d *tracedDatabase) ListAuditRecords(
+	ctx context.Context, filter database.AuditFilter, cursor string, limit int,
+) ([]*model.AuditRecord, string, error) {
+	ctx, span := d.start(ctx, "ListAuditRecords", attribute.Int("db.limit", limit))
+	records, next, err := d.db.ListAuditRecords(ctx, filter, cursor, limit)
+	d.end(span, err)
+	return records, next, err
+}
+
+func (d *tracedDatabase) InsertIdempotencyRecord(ctx context.Context, record *model.IdempotencyRecord) error {
+	ctx, span := d.start(ctx, "InsertIdempotencyRecord")
+	err := d.db.InsertIdempotencyRecord(ctx, record)
+	d.end(span, err)
+	return err
+}
+
+func (d *tracedDatabase) GetIdempotencyRecord(ctx context.Context, key string) (*model.IdempotencyRecord, error) {
+	ctx, span := d.start(ctx, "GetIdempotencyRecord")
+	record, err := d.db.GetIdempotencyRecord(ctx, key)
+	d.end(span, err)
+	return record, err
+}
+
+func (d *tracedDatabase) UpdateIdempotencyRecord(ctx context.Context, record *model.IdempotencyRecord) error {
+	ctx, span := d.start(ctx, "UpdateIdempotencyRecord")
+	err := d.db.UpdateIdempotencyRecord(ctx, record)
+	d.end(span, err)
+	return err
+}
+
+func (d *tracedDatabase) DeleteIdempotencyRecord(ctx context.Context, key string) error {
+	ctx, span := d.start(ctx, "DeleteIdempotencyRecord")
+	err := d.db.DeleteIdempotencyRecord(ctx, key)
+	d.end(span, err)
+	return err
+}
+
+func (d *tracedDatabase) IncrementCounter(
+	ctx context.Context, key string, window time.Duration,
+) (int, time.Time, error) {
+	ctx, span := d.start(ctx, "IncrementCounter")
+	count, resetAt, err := d.db.IncrementCounter(ctx, key, window)
+	d.end(span, err)
+	return count, resetAt, err
+}
+
+func (d *tracedDatabase) DecrementCounter(
+	ctx context.Context, key string, window time.Duration, resetAt time.Time,
+) error {
+	ctx, span := d.start(ctx, "DecrementCounter")
+	err := d.db.DecrementCounter(ctx, key, window, resetAt)
+	d.end(span, err)
+	return err
+}
+
+func (d *tracedDatabase) Close() error {
+	return d.db.Close()
+}
@@ -0,0 +1,123 @@
+// Package tracing sets up OpenTelemetry tracing and provides helpers to create spans
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/modelcontextprotocol/registry/internal/api/requestid"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the registry's own spans
+const instrumentationName = "github.com/modelcontextprotocol/registry"
+
+// serviceName is reported as service.name unless overridden by OTEL_SERVICE_NAME
+const serviceName = "mcp-registry"
+
+// Setup installs the global tracer provider and propagator according to the configuration and
+// returns a function that flushes and shuts the provider down. With the "none" exporter the
+// global no-op provider is left in place, so spans cost next to nothing.
+func Setup(ctx context.Context, cfg *config.Config) (func(context.Context) error, error) {
+	// Propagate trace context to outbound calls even when spans aren't exported,
+	// so that an upstream trace isn't broken by the registry
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	switch cfg.TracingExporter {
+	case config.TracingExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case config.TracingExporterOTLP:
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter: %s", cfg.TracingExporter)
+	}
+
+	var opts []otlptracehttp.Option
+	if cfg.OTLPEndpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint))
+	}
+	if cfg.OTLPInsecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName), semconv.ServiceVersion(cfg.Version)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Start creates a span as a child of any span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Middleware starts a server span for every request, continuing any trace propagated by the
+// caller. Once the router has matched the request the span is renamed after the route pattern.
+func Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			span := trace.SpanFromContext(r.Context())
+			if id := requestid.FromContext(r.Context()); id != "" {
+				span.SetAttributes(attribute.String("request.id", id))
+			}
+
+			next.ServeHTTP(w, r)
+
+			// ServeMux sets the pattern on the request once it has matched a route
+			if r.Pattern != "" {
+				span.SetName(r.Method + " " + r.Pattern)
+				span.SetAttributes(semconv.HTTPRoute(r.Pattern))
+			}
+		})
+		return otelhttp.NewHandler(inner, "http.request",
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method
+			}),
+		)
+	}
+}
+
+// Transport wraps base so that outbound requests are traced and carry the trace context.
+// A nil base uses http.DefaultTransport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
@@ -0,0 +1,58 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/model"
+	"github.com/modelcontextprotocol/registry/internal/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider that records finished spans for the duration of the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+func TestMiddlewarePropagatesSpansToDatabase(t *testing.T) {
+	recorder := recordSpans(t)
+
+	db := tracing.InstrumentDatabase(database.NewMemoryDB(map[string]*model.Server{}), "memory")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v0/servers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, err := db.GetByID(r.Context(), r.PathValue("id"))
+		assert.ErrorIs(t, err, database.ErrNotFound)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	rr := httptest.NewRecorder()
+	tracing.Middleware()(mux).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v0/servers/abc", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	dbSpan, serverSpan := spans[0], spans[1]
+	assert.Equal(t, "db.GetByID", dbSpan.Name())
+	assert.Equal(t, "GET /v0/servers/{id}", serverSpan.Name())
+	assert.Equal(t, serverSpan.SpanContext().TraceID(), dbSpan.SpanContext().TraceID())
+	assert.Equal(t, serverSpan.SpanContext().SpanID(), dbSpan.Parent().SpanID())
+}
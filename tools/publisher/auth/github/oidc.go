@@ -0,0 +1,87 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// DefaultOIDCAudience is the audience the registry expects GitHub Actions OIDC tokens for
+const DefaultOIDCAudience = "mcp-registry"
+
+// OIDCProvider implements the AuthProvider interface with the OIDC token of a GitHub Actions run.
+// The workflow needs the `id-token: write` permission.
+type OIDCProvider struct {
+	audience string
+}
+
+// NewOIDCProvider creates a new GitHub Actions OIDC provider requesting tokens for audience
+func NewOIDCProvider(audience string) *OIDCProvider {
+	return &OIDCProvider{audience: audience}
+}
+
+// GetToken requests an OIDC token from the GitHub Actions runtime
+func (o *OIDCProvider) GetToken(ctx context.Context) (string, error) {
+	requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+	if requestURL == "" || requestToken == "" {
+		return "", errors.New("not running in GitHub Actions with the id-token: write permission")
+	}
+
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid ACTIONS_ID_TOKEN_REQUEST_URL: %w", err)
+	}
+	query := u.Query()
+	query.Set("audience", o.audience)
+	u.RawQuery = query.Encode()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+requestToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request OIDC token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to request OIDC token: status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode OIDC token response: %w", err)
+	}
+	if body.Value == "" {
+		return "", errors.New("GitHub Actions returned an empty OIDC token")
+	}
+	return body.Value, nil
+}
+
+// NeedsLogin always returns false, since tokens are requested for every publish
+func (o *OIDCProvider) NeedsLogin() bool {
+	return false
+}
+
+// Login is a no-op for OIDC tokens
+func (o *OIDCProvider) Login(_ context.Context) error {
+	return nil
+}
+
+// Name returns the name of the authentication provider
+func (o *OIDCProvider) Name() string {
+	return "github-oidc"
+}
@@ -93,12 +93,14 @@ func publishCommand() {
 	var mcpFilePath string
 	var forceLogin bool
 	var authMethod string
+	var oidcAudience string
 
 	// Command-line flags for configuration
 	publishFlags.StringVar(&registryURL, "registry-url", "", "URL of the registry (required)")
 	publishFlags.StringVar(&mcpFilePath, "mcp-file", "", "path to the MCP file (required)")
 	publishFlags.BoolVar(&forceLogin, "login", false, "force a new login even if a token exists")
 	publishFlags.StringVar(&authMethod, "auth-method", "github-oauth", "authentication method to use (default: github-oauth)")
+	publishFlags.StringVar(&oidcAudience, "oidc-audience", github.DefaultOIDCAudience, "audience of GitHub Actions OIDC tokens")
 
 	// Set custom usage function
 	publishFlags.Usage = func() {
@@ -110,7 +112,8 @@ func publishCommand() {
 		fmt.Fprint(os.Stdout, "  --registry-url string    URL of the registry (required)\n")
 		fmt.Fprint(os.Stdout, "  --mcp-file string        path to the MCP file (required)\n")
 		fmt.Fprint(os.Stdout, "  --login                  force a new login even if a token exists\n")
-		fmt.Fprint(os.Stdout, "  --auth-method string     authentication method to use (default: github-oauth)\n")
+		fmt.Fprint(os.Stdout, "  --auth-method string     authentication method to use: github-oauth or github-oidc (default: github-oauth)\n")
+		fmt.Fprint(os.Stdout, "  --oidc-audience string   audience of GitHub Actions OIDC tokens (default: mcp-registry)\n")
 	}
 
 	if err := publishFlags.Parse(os.Args[2:]); err != nil {
@@ -134,6 +137,9 @@ func publishCommand() {
 	case "github-oauth":
 		log.Println("Using GitHub OAuth for authentication")
 		authProvider = github.NewOAuthProvider(forceLogin, registryURL)
+	case "github-oidc":
+		log.Println("Using GitHub Actions OIDC for authentication")
+		authProvider = github.NewOIDCProvider(oidcAudience)
 	default:
 		log.Printf("Unsupported authentication method: %s\n", authMethod)
 		return